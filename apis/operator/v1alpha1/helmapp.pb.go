@@ -87,6 +87,18 @@ type HelmAppSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	GlobalValues *structpb.Struct `protobuf:"bytes,2,opt,name=globalValues,proto3" json:"globalValues,omitempty"`
 	Repo         *HelmRepo        `protobuf:"bytes,3,opt,name=repo,proto3" json:"repo,omitempty"`
+	// Render the string values of globalValues and componentValues as Go templates
+	ValuesTemplating *ValuesTemplating `protobuf:"bytes,4,opt,name=valuesTemplating,proto3" json:"valuesTemplating,omitempty"`
+	// JSON paths of the values masked in logs, events and status messages, e.g.
+	// `$.global.proxy.credentials` or `gateways[*].auth`. Keys like `password`,
+	// `token`, `clientSecret` or `apiKey` are always masked.
+	RedactPaths []string `protobuf:"bytes,5,rep,name=redactPaths,proto3" json:"redactPaths,omitempty"`
+	// Strategies merging the lists of componentValues into the lists of globalValues
+	// by dotted path, e.g. `global.proxy.env: mergeByName`. Either `replace` (default),
+	// `append`, `mergeByName` or `mergeByKey:<field>`. An explicit null unsets a key.
+	MergeStrategies map[string]string `protobuf:"bytes,6,rep,name=mergeStrategies,proto3" json:"mergeStrategies,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Publish the values given to Helm for each component once installed or upgraded
+	EffectiveValues *EffectiveValues `protobuf:"bytes,7,opt,name=effectiveValues,proto3" json:"effectiveValues,omitempty"`
 }
 
 func (x *HelmAppSpec) Reset() {
@@ -142,6 +154,142 @@ func (x *HelmAppSpec) GetRepo() *HelmRepo {
 	return nil
 }
 
+func (x *HelmAppSpec) GetValuesTemplating() *ValuesTemplating {
+	if x != nil {
+		return x.ValuesTemplating
+	}
+	return nil
+}
+
+func (x *HelmAppSpec) GetRedactPaths() []string {
+	if x != nil {
+		return x.RedactPaths
+	}
+	return nil
+}
+
+func (x *HelmAppSpec) GetMergeStrategies() map[string]string {
+	if x != nil {
+		return x.MergeStrategies
+	}
+	return nil
+}
+
+func (x *HelmAppSpec) GetEffectiveValues() *EffectiveValues {
+	if x != nil {
+		return x.EffectiveValues
+	}
+	return nil
+}
+
+type EffectiveValues struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// `ConfigMap` writes the values to a ConfigMap owned by the HelmApp named
+	// `<helmapp>-<component>-values`, `Status` to the component status for small
+	// payloads. Sensitive values are masked.
+	// +kubebuilder:validation:Enum=ConfigMap;Status
+	Target string `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+}
+
+func (x *EffectiveValues) Reset() {
+	*x = EffectiveValues{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EffectiveValues) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EffectiveValues) ProtoMessage() {}
+
+func (x *EffectiveValues) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EffectiveValues.ProtoReflect.Descriptor instead.
+func (*EffectiveValues) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EffectiveValues) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type ValuesTemplating struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// ConfigMaps in the HelmApp namespace whose data is available to the templates
+	// as `.ConfigMaps.<name>.<key>`, e.g. a cluster name
+	ConfigMaps []string `protobuf:"bytes,2,rep,name=configMaps,proto3" json:"configMaps,omitempty"`
+}
+
+func (x *ValuesTemplating) Reset() {
+	*x = ValuesTemplating{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ValuesTemplating) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValuesTemplating) ProtoMessage() {}
+
+func (x *ValuesTemplating) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValuesTemplating.ProtoReflect.Descriptor instead.
+func (*ValuesTemplating) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValuesTemplating) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ValuesTemplating) GetConfigMaps() []string {
+	if x != nil {
+		return x.ConfigMaps
+	}
+	return nil
+}
+
 type HelmComponent struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -154,27 +302,622 @@ type HelmComponent struct {
 	ComponentValues    *structpb.Struct `protobuf:"bytes,4,opt,name=componentValues,proto3" json:"componentValues,omitempty"`
 	Repo               *HelmRepo        `protobuf:"bytes,5,opt,name=repo,proto3" json:"repo,omitempty"`
 	IgnoreGlobalValues bool             `protobuf:"varint,6,opt,name=ignoreGlobalValues,proto3" json:"ignoreGlobalValues,omitempty"`
-	// Enable schema validation for this component
+	// Filter the values by the schema of the chart, same as `schemaValidation: Filter`
 	EnableSchemaValidation bool `protobuf:"varint,7,opt,name=enableSchemaValidation,proto3" json:"enableSchemaValidation,omitempty"`
+	// Digest the pulled chart must match, e.g. `sha256:...`. For OCI charts this
+	// is the manifest digest, for HTTP repos the digest of the chart archive as
+	// listed in index.yaml.
+	Digest string `protobuf:"bytes,8,opt,name=digest,proto3" json:"digest,omitempty"`
+	// Verification policy of the chart, overrides the one of the repo
+	Verification *ChartVerification `protobuf:"bytes,9,opt,name=verification,proto3" json:"verification,omitempty"`
+	// Local source of the chart, used instead of the repo when set. It cannot be
+	// verified, a verification policy of the component or its repo fails the install.
+	ChartSource *ChartSource `protobuf:"bytes,10,opt,name=chartSource,proto3" json:"chartSource,omitempty"`
+	// How the values are checked against the values.schema.json of the chart.
+	// `Filter` drops the properties unknown to the schema, `Validate` blocks the
+	// install or upgrade when values violate the schema and reports every
+	// violation in the status, `FilterAndValidate` validates the filtered values.
+	// +kubebuilder:validation:Enum=Filter;Validate;FilterAndValidate
+	SchemaValidation string `protobuf:"bytes,11,opt,name=schemaValidation,proto3" json:"schemaValidation,omitempty"`
+	// Fill the properties missing from both the values and the values.yaml of the
+	// chart with the `default` of the chart schema, the injected defaults are
+	// reported in the status
+	ApplySchemaDefaults bool `protobuf:"varint,12,opt,name=applySchemaDefaults,proto3" json:"applySchemaDefaults,omitempty"`
+	// Schema inferred from the default values of charts without
+	// values.schema.json, used to filter the values
+	SchemaInference *SchemaInference `protobuf:"bytes,13,opt,name=schemaInference,proto3" json:"schemaInference,omitempty"`
+	// Namespace the release is installed in, the namespace of the HelmApp when
+	// empty. The namespace must exist.
+	Namespace string `protobuf:"bytes,14,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Patches applied to the resources rendered by the chart before they are
+	// installed, the patches matching nothing are reported in the status
+	Overlays []*HelmOverlay `protobuf:"bytes,15,rep,name=overlays,proto3" json:"overlays,omitempty"`
 }
 
 func (x *HelmComponent) Reset() {
 	*x = HelmComponent{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[1]
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HelmComponent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelmComponent) ProtoMessage() {}
+
+func (x *HelmComponent) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelmComponent.ProtoReflect.Descriptor instead.
+func (*HelmComponent) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *HelmComponent) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HelmComponent) GetChart() string {
+	if x != nil {
+		return x.Chart
+	}
+	return ""
+}
+
+func (x *HelmComponent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *HelmComponent) GetComponentValues() *structpb.Struct {
+	if x != nil {
+		return x.ComponentValues
+	}
+	return nil
+}
+
+func (x *HelmComponent) GetRepo() *HelmRepo {
+	if x != nil {
+		return x.Repo
+	}
+	return nil
+}
+
+func (x *HelmComponent) GetIgnoreGlobalValues() bool {
+	if x != nil {
+		return x.IgnoreGlobalValues
+	}
+	return false
+}
+
+func (x *HelmComponent) GetEnableSchemaValidation() bool {
+	if x != nil {
+		return x.EnableSchemaValidation
+	}
+	return false
+}
+
+func (x *HelmComponent) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *HelmComponent) GetVerification() *ChartVerification {
+	if x != nil {
+		return x.Verification
+	}
+	return nil
+}
+
+func (x *HelmComponent) GetChartSource() *ChartSource {
+	if x != nil {
+		return x.ChartSource
+	}
+	return nil
+}
+
+func (x *HelmComponent) GetSchemaValidation() string {
+	if x != nil {
+		return x.SchemaValidation
+	}
+	return ""
+}
+
+func (x *HelmComponent) GetApplySchemaDefaults() bool {
+	if x != nil {
+		return x.ApplySchemaDefaults
+	}
+	return false
+}
+
+func (x *HelmComponent) GetSchemaInference() *SchemaInference {
+	if x != nil {
+		return x.SchemaInference
+	}
+	return nil
+}
+
+func (x *HelmComponent) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HelmComponent) GetOverlays() []*HelmOverlay {
+	if x != nil {
+		return x.Overlays
+	}
+	return nil
+}
+
+type SchemaInference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Infer a permissive schema when the chart has no values.schema.json and the
+	// values are filtered: keys missing from the default values are dropped,
+	// maps with an empty default allow any key
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Free-form paths allowing any key, in addition to `podAnnotations`,
+	// `labels`, `resources`, ... A dotted path where `*` matches any key, a
+	// single key matches at any depth, e.g. `meshConfig.defaultConfig.proxyMetadata`
+	AllowedPaths []string `protobuf:"bytes,2,rep,name=allowedPaths,proto3" json:"allowedPaths,omitempty"`
+}
+
+func (x *SchemaInference) Reset() {
+	*x = SchemaInference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SchemaInference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaInference) ProtoMessage() {}
+
+func (x *SchemaInference) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaInference.ProtoReflect.Descriptor instead.
+func (*SchemaInference) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SchemaInference) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *SchemaInference) GetAllowedPaths() []string {
+	if x != nil {
+		return x.AllowedPaths
+	}
+	return nil
+}
+
+type HelmOverlay struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// API version of the patched resource, ignored like in IstioOperator overlays
+	ApiVersion string              `protobuf:"bytes,1,opt,name=apiVersion,proto3" json:"apiVersion,omitempty"`
+	Kind       string              `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Name       string              `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Patches    []*HelmOverlayPatch `protobuf:"bytes,4,rep,name=patches,proto3" json:"patches,omitempty"`
+}
+
+func (x *HelmOverlay) Reset() {
+	*x = HelmOverlay{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HelmOverlay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelmOverlay) ProtoMessage() {}
+
+func (x *HelmOverlay) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelmOverlay.ProtoReflect.Descriptor instead.
+func (*HelmOverlay) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *HelmOverlay) GetApiVersion() string {
+	if x != nil {
+		return x.ApiVersion
+	}
+	return ""
+}
+
+func (x *HelmOverlay) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *HelmOverlay) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HelmOverlay) GetPatches() []*HelmOverlayPatch {
+	if x != nil {
+		return x.Patches
+	}
+	return nil
+}
+
+type HelmOverlayPatch struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Path of the patched value, list items are selected by index `[0]`, by key
+	// `[name:istio-proxy]` or by value `[.*-proxy]`, e.g.
+	// `spec.template.spec.containers.[name:istio-proxy].args`
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// Value set at the path, the value is deleted when unset
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Value *structpb.Value `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (x *HelmOverlayPatch) Reset() {
+	*x = HelmOverlayPatch{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HelmOverlayPatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelmOverlayPatch) ProtoMessage() {}
+
+func (x *HelmOverlayPatch) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelmOverlayPatch.ProtoReflect.Descriptor instead.
+func (*HelmOverlayPatch) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HelmOverlayPatch) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *HelmOverlayPatch) GetValue() *structpb.Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type HelmRepo struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url  string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Secret in the HelmApp namespace holding the repository credentials.
+	// Supported keys: `username` and `password` for basic auth, `token` for
+	// bearer auth, `tls.crt` and `tls.key` for a client certificate and
+	// `ca.crt` for a custom CA bundle.
+	SecretRef *SecretReference `protobuf:"bytes,3,opt,name=secretRef,proto3" json:"secretRef,omitempty"`
+	// Skip verification of the repository server certificate
+	InsecureSkipTLSVerify bool `protobuf:"varint,4,opt,name=insecureSkipTLSVerify,proto3" json:"insecureSkipTLSVerify,omitempty"`
+	// Pass credentials to all domains, not only the repository host
+	PassCredentialsAll bool `protobuf:"varint,5,opt,name=passCredentialsAll,proto3" json:"passCredentialsAll,omitempty"`
+	// Verification policy of the charts pulled from this repo
+	Verification *ChartVerification `protobuf:"bytes,6,opt,name=verification,proto3" json:"verification,omitempty"`
+}
+
+func (x *HelmRepo) Reset() {
+	*x = HelmRepo{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *HelmRepo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HelmRepo) ProtoMessage() {}
+
+func (x *HelmRepo) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HelmRepo.ProtoReflect.Descriptor instead.
+func (*HelmRepo) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *HelmRepo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HelmRepo) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *HelmRepo) GetSecretRef() *SecretReference {
+	if x != nil {
+		return x.SecretRef
+	}
+	return nil
+}
+
+func (x *HelmRepo) GetInsecureSkipTLSVerify() bool {
+	if x != nil {
+		return x.InsecureSkipTLSVerify
+	}
+	return false
+}
+
+func (x *HelmRepo) GetPassCredentialsAll() bool {
+	if x != nil {
+		return x.PassCredentialsAll
+	}
+	return false
+}
+
+func (x *HelmRepo) GetVerification() *ChartVerification {
+	if x != nil {
+		return x.Verification
+	}
+	return nil
+}
+
+type SecretReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *SecretReference) Reset() {
+	*x = SecretReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SecretReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SecretReference) ProtoMessage() {}
+
+func (x *SecretReference) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SecretReference.ProtoReflect.Descriptor instead.
+func (*SecretReference) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SecretReference) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type ChartSource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Path of a chart directory or packaged .tgz inside the operator image or a
+	// mounted volume, e.g. `istio/sample-charts/istio`
+	Path string `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	// ConfigMap in the HelmApp namespace holding the packaged chart
+	ConfigMapRef *ChartArchiveReference `protobuf:"bytes,2,opt,name=configMapRef,proto3" json:"configMapRef,omitempty"`
+	// Secret in the HelmApp namespace holding the packaged chart
+	SecretRef *ChartArchiveReference `protobuf:"bytes,3,opt,name=secretRef,proto3" json:"secretRef,omitempty"`
+	// Git repository holding the chart
+	Git *GitChartSource `protobuf:"bytes,4,opt,name=git,proto3" json:"git,omitempty"`
+}
+
+func (x *ChartSource) Reset() {
+	*x = ChartSource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ChartSource) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChartSource) ProtoMessage() {}
+
+func (x *ChartSource) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChartSource.ProtoReflect.Descriptor instead.
+func (*ChartSource) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ChartSource) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *ChartSource) GetConfigMapRef() *ChartArchiveReference {
+	if x != nil {
+		return x.ConfigMapRef
+	}
+	return nil
+}
+
+func (x *ChartSource) GetSecretRef() *ChartArchiveReference {
+	if x != nil {
+		return x.SecretRef
+	}
+	return nil
+}
+
+func (x *ChartSource) GetGit() *GitChartSource {
+	if x != nil {
+		return x.Git
+	}
+	return nil
+}
+
+type GitChartSource struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Repository url, e.g. `https://github.com/example/charts.git` or
+	// `ssh://git@github.com/example/charts.git`
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Branch, tag or full commit SHA to load the chart from, defaults to the
+	// default branch of the repository
+	Ref string `protobuf:"bytes,2,opt,name=ref,proto3" json:"ref,omitempty"`
+	// Path of the chart directory in the repository
+	Path string `protobuf:"bytes,3,opt,name=path,proto3" json:"path,omitempty"`
+	// Secret in the HelmApp namespace holding the credentials. Supported keys:
+	// `username` and `password` or `token` for HTTP(S), `identity` (private key)
+	// and `known_hosts` for SSH.
+	SecretRef *SecretReference `protobuf:"bytes,4,opt,name=secretRef,proto3" json:"secretRef,omitempty"`
+}
+
+func (x *GitChartSource) Reset() {
+	*x = GitChartSource{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *HelmComponent) String() string {
+func (x *GitChartSource) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HelmComponent) ProtoMessage() {}
+func (*GitChartSource) ProtoMessage() {}
 
-func (x *HelmComponent) ProtoReflect() protoreflect.Message {
-	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[1]
+func (x *GitChartSource) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -185,86 +928,128 @@ func (x *HelmComponent) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HelmComponent.ProtoReflect.Descriptor instead.
-func (*HelmComponent) Descriptor() ([]byte, []int) {
-	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use GitChartSource.ProtoReflect.Descriptor instead.
+func (*GitChartSource) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *HelmComponent) GetName() string {
+func (x *GitChartSource) GetUrl() string {
 	if x != nil {
-		return x.Name
+		return x.Url
 	}
 	return ""
 }
 
-func (x *HelmComponent) GetChart() string {
+func (x *GitChartSource) GetRef() string {
 	if x != nil {
-		return x.Chart
+		return x.Ref
 	}
 	return ""
 }
 
-func (x *HelmComponent) GetVersion() string {
+func (x *GitChartSource) GetPath() string {
 	if x != nil {
-		return x.Version
+		return x.Path
 	}
 	return ""
 }
 
-func (x *HelmComponent) GetComponentValues() *structpb.Struct {
+func (x *GitChartSource) GetSecretRef() *SecretReference {
 	if x != nil {
-		return x.ComponentValues
+		return x.SecretRef
 	}
 	return nil
 }
 
-func (x *HelmComponent) GetRepo() *HelmRepo {
-	if x != nil {
-		return x.Repo
+type ChartArchiveReference struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Key of the packaged .tgz, defaults to `chart.tgz`. Archives too large for
+	// a single key can be split into chunks named `<key>.0`, `<key>.1`, ...
+	// which are concatenated in order.
+	Key string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (x *ChartArchiveReference) Reset() {
+	*x = ChartArchiveReference{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *HelmComponent) GetIgnoreGlobalValues() bool {
+func (x *ChartArchiveReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChartArchiveReference) ProtoMessage() {}
+
+func (x *ChartArchiveReference) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChartArchiveReference.ProtoReflect.Descriptor instead.
+func (*ChartArchiveReference) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ChartArchiveReference) GetName() string {
 	if x != nil {
-		return x.IgnoreGlobalValues
+		return x.Name
 	}
-	return false
+	return ""
 }
 
-func (x *HelmComponent) GetEnableSchemaValidation() bool {
+func (x *ChartArchiveReference) GetKey() string {
 	if x != nil {
-		return x.EnableSchemaValidation
+		return x.Key
 	}
-	return false
+	return ""
 }
 
-type HelmRepo struct {
+type ChartVerification struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Url  string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// `Provenance` checks the helm .prov file against a keyring, `Cosign`
+	// checks the cosign signatures of an OCI chart against public keys
+	// +kubebuilder:validation:Enum=Provenance;Cosign
+	Mode string `protobuf:"bytes,1,opt,name=mode,proto3" json:"mode,omitempty"`
+	// Secret in the HelmApp namespace holding the keys: `keyring.gpg` for
+	// `Provenance`, one or more PEM public keys named `*.pub` for `Cosign`
+	SecretRef *SecretReference `protobuf:"bytes,2,opt,name=secretRef,proto3" json:"secretRef,omitempty"`
 }
 
-func (x *HelmRepo) Reset() {
-	*x = HelmRepo{}
+func (x *ChartVerification) Reset() {
+	*x = ChartVerification{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[2]
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[12]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *HelmRepo) String() string {
+func (x *ChartVerification) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HelmRepo) ProtoMessage() {}
+func (*ChartVerification) ProtoMessage() {}
 
-func (x *HelmRepo) ProtoReflect() protoreflect.Message {
-	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[2]
+func (x *ChartVerification) ProtoReflect() protoreflect.Message {
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[12]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -275,23 +1060,23 @@ func (x *HelmRepo) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HelmRepo.ProtoReflect.Descriptor instead.
-func (*HelmRepo) Descriptor() ([]byte, []int) {
-	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ChartVerification.ProtoReflect.Descriptor instead.
+func (*ChartVerification) Descriptor() ([]byte, []int) {
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *HelmRepo) GetName() string {
+func (x *ChartVerification) GetMode() string {
 	if x != nil {
-		return x.Name
+		return x.Mode
 	}
 	return ""
 }
 
-func (x *HelmRepo) GetUrl() string {
+func (x *ChartVerification) GetSecretRef() *SecretReference {
 	if x != nil {
-		return x.Url
+		return x.SecretRef
 	}
-	return ""
+	return nil
 }
 
 type HelmAppStatus struct {
@@ -308,7 +1093,7 @@ type HelmAppStatus struct {
 func (x *HelmAppStatus) Reset() {
 	*x = HelmAppStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[3]
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -321,7 +1106,7 @@ func (x *HelmAppStatus) String() string {
 func (*HelmAppStatus) ProtoMessage() {}
 
 func (x *HelmAppStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[3]
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -334,7 +1119,7 @@ func (x *HelmAppStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HelmAppStatus.ProtoReflect.Descriptor instead.
 func (*HelmAppStatus) Descriptor() ([]byte, []int) {
-	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{3}
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *HelmAppStatus) GetPhase() Phase {
@@ -362,12 +1147,40 @@ type HelmComponentStatus struct {
 	Version        string                `protobuf:"bytes,4,opt,name=version,proto3" json:"version,omitempty"`
 	Resources      []*HelmResourceStatus `protobuf:"bytes,5,rep,name=resources,proto3" json:"resources,omitempty"`
 	ResourcesTotal int32                 `protobuf:"varint,6,opt,name=resourcesTotal,proto3" json:"resourcesTotal,omitempty"`
+	// Digest of the chart that was pulled
+	Digest string `protobuf:"bytes,7,opt,name=digest,proto3" json:"digest,omitempty"`
+	// Machine readable reason of the status, e.g. `VerificationFailed`
+	Reason string `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Commit of the Git chart source the chart was loaded from
+	Commit string `protobuf:"bytes,9,opt,name=commit,proto3" json:"commit,omitempty"`
+	// Hash of the values given to Helm, set when the effective values are published
+	ValuesHash string `protobuf:"bytes,10,opt,name=valuesHash,proto3" json:"valuesHash,omitempty"`
+	// Values given to Helm, set when the effective values are published to the status
+	// +kubebuilder:pruning:PreserveUnknownFields
+	EffectiveValues *structpb.Struct `protobuf:"bytes,11,opt,name=effectiveValues,proto3" json:"effectiveValues,omitempty"`
+	// Layers each top-level key of the effective values was set by, e.g.
+	// `globalValues,componentValues`
+	ValuesLayers map[string]string `protobuf:"bytes,12,rep,name=valuesLayers,proto3" json:"valuesLayers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// Paths of the values filled from the defaults of the chart schema
+	InjectedDefaults []string `protobuf:"bytes,13,rep,name=injectedDefaults,proto3" json:"injectedDefaults,omitempty"`
+	// Paths of the values dropped because they are not in the chart schema
+	PrunedPaths []string `protobuf:"bytes,14,rep,name=prunedPaths,proto3" json:"prunedPaths,omitempty"`
+	// Why the release was installed or upgraded by the last reconcile, e.g.
+	// `ValuesChanged` or `ChartChanged`, `UpToDate` when it was not
+	UpgradeReason string `protobuf:"bytes,15,opt,name=upgradeReason,proto3" json:"upgradeReason,omitempty"`
+	// Version of the chart of the deployed release, `version` is the revision of the release
+	ChartVersion string `protobuf:"bytes,16,opt,name=chartVersion,proto3" json:"chartVersion,omitempty"`
+	// Namespace of the release
+	Namespace string `protobuf:"bytes,17,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Overlays and overlay patches which matched no rendered resource or value,
+	// e.g. `Deployment/istiod: spec.template.spec.containers.[name:discovery].args`
+	UnmatchedOverlays []string `protobuf:"bytes,18,rep,name=unmatchedOverlays,proto3" json:"unmatchedOverlays,omitempty"`
 }
 
 func (x *HelmComponentStatus) Reset() {
 	*x = HelmComponentStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[4]
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -380,7 +1193,7 @@ func (x *HelmComponentStatus) String() string {
 func (*HelmComponentStatus) ProtoMessage() {}
 
 func (x *HelmComponentStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[4]
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -393,7 +1206,7 @@ func (x *HelmComponentStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HelmComponentStatus.ProtoReflect.Descriptor instead.
 func (*HelmComponentStatus) Descriptor() ([]byte, []int) {
-	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{4}
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *HelmComponentStatus) GetName() string {
@@ -438,6 +1251,90 @@ func (x *HelmComponentStatus) GetResourcesTotal() int32 {
 	return 0
 }
 
+func (x *HelmComponentStatus) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *HelmComponentStatus) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *HelmComponentStatus) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *HelmComponentStatus) GetValuesHash() string {
+	if x != nil {
+		return x.ValuesHash
+	}
+	return ""
+}
+
+func (x *HelmComponentStatus) GetEffectiveValues() *structpb.Struct {
+	if x != nil {
+		return x.EffectiveValues
+	}
+	return nil
+}
+
+func (x *HelmComponentStatus) GetValuesLayers() map[string]string {
+	if x != nil {
+		return x.ValuesLayers
+	}
+	return nil
+}
+
+func (x *HelmComponentStatus) GetInjectedDefaults() []string {
+	if x != nil {
+		return x.InjectedDefaults
+	}
+	return nil
+}
+
+func (x *HelmComponentStatus) GetPrunedPaths() []string {
+	if x != nil {
+		return x.PrunedPaths
+	}
+	return nil
+}
+
+func (x *HelmComponentStatus) GetUpgradeReason() string {
+	if x != nil {
+		return x.UpgradeReason
+	}
+	return ""
+}
+
+func (x *HelmComponentStatus) GetChartVersion() string {
+	if x != nil {
+		return x.ChartVersion
+	}
+	return ""
+}
+
+func (x *HelmComponentStatus) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HelmComponentStatus) GetUnmatchedOverlays() []string {
+	if x != nil {
+		return x.UnmatchedOverlays
+	}
+	return nil
+}
+
 type HelmResourceStatus struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -452,7 +1349,7 @@ type HelmResourceStatus struct {
 func (x *HelmResourceStatus) Reset() {
 	*x = HelmResourceStatus{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[5]
+		mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -465,7 +1362,7 @@ func (x *HelmResourceStatus) String() string {
 func (*HelmResourceStatus) ProtoMessage() {}
 
 func (x *HelmResourceStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[5]
+	mi := &file_operator_v1alpha1_helmapp_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -478,7 +1375,7 @@ func (x *HelmResourceStatus) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HelmResourceStatus.ProtoReflect.Descriptor instead.
 func (*HelmResourceStatus) Descriptor() ([]byte, []int) {
-	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{5}
+	return file_operator_v1alpha1_helmapp_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *HelmResourceStatus) GetApiVersion() string {
@@ -519,7 +1416,7 @@ var file_operator_v1alpha1_helmapp_proto_rawDesc = []byte{
 	0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75,
 	0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
-	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xc9, 0x01, 0x0a, 0x0b, 0x48, 0x65,
+	0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xbf, 0x04, 0x0a, 0x0b, 0x48, 0x65,
 	0x6c, 0x6d, 0x41, 0x70, 0x70, 0x53, 0x70, 0x65, 0x63, 0x12, 0x46, 0x0a, 0x0a, 0x63, 0x6f, 0x6d,
 	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26, 0x2e,
 	0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76,
@@ -532,69 +1429,238 @@ var file_operator_v1alpha1_helmapp_proto_rawDesc = []byte{
 	0x0a, 0x04, 0x72, 0x65, 0x70, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70,
 	0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31,
 	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x52,
-	0x04, 0x72, 0x65, 0x70, 0x6f, 0x22, 0xb5, 0x02, 0x0a, 0x0d, 0x48, 0x65, 0x6c, 0x6d, 0x43, 0x6f,
-	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63,
-	0x68, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x61, 0x72,
-	0x74, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0f, 0x63,
-	0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x0f, 0x63,
-	0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x35,
-	0x0a, 0x04, 0x72, 0x65, 0x70, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70,
+	0x04, 0x72, 0x65, 0x70, 0x6f, 0x12, 0x55, 0x0a, 0x10, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x54,
+	0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x29, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x52, 0x10, 0x76, 0x61, 0x6c, 0x75,
+	0x65, 0x73, 0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x20, 0x0a, 0x0b,
+	0x72, 0x65, 0x64, 0x61, 0x63, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x61, 0x63, 0x74, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x63,
+	0x0a, 0x0f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x39, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x41, 0x70, 0x70, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x4d, 0x65,
+	0x72, 0x67, 0x65, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x52, 0x0f, 0x6d, 0x65, 0x72, 0x67, 0x65, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67,
+	0x69, 0x65, 0x73, 0x12, 0x52, 0x0a, 0x0f, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70,
 	0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31,
-	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x52,
-	0x04, 0x72, 0x65, 0x70, 0x6f, 0x12, 0x2e, 0x0a, 0x12, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x47,
-	0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x12, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x56,
-	0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x16, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x53,
-	0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x63, 0x68,
-	0x65, 0x6d, 0x61, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x30, 0x0a,
-	0x08, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a,
-	0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x22,
-	0x93, 0x01, 0x0a, 0x0d, 0x48, 0x65, 0x6c, 0x6d, 0x41, 0x70, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x34, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x1e, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
-	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x68, 0x61, 0x73, 0x65,
-	0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f,
-	0x6e, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70, 0x6c,
+	0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65,
+	0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x52, 0x0f, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76,
+	0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x1a, 0x42, 0x0a, 0x14, 0x4d, 0x65, 0x72, 0x67, 0x65,
+	0x53, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x69, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x29, 0x0a, 0x0f, 0x45,
+	0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x16,
+	0x0a, 0x06, 0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x74, 0x61, 0x72, 0x67, 0x65, 0x74, 0x22, 0x4c, 0x0a, 0x10, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73,
+	0x54, 0x65, 0x6d, 0x70, 0x6c, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61,
+	0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x4d, 0x61, 0x70, 0x73, 0x22, 0xf7, 0x05, 0x0a, 0x0d, 0x48, 0x65, 0x6c, 0x6d, 0x43, 0x6f, 0x6d,
+	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x68,
+	0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x68, 0x61, 0x72, 0x74,
+	0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x41, 0x0a, 0x0f, 0x63, 0x6f,
+	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x0f, 0x63, 0x6f,
+	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x35, 0x0a,
+	0x04, 0x72, 0x65, 0x70, 0x6f, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x6c,
 	0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61,
-	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e,
-	0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f,
-	0x6e, 0x65, 0x6e, 0x74, 0x73, 0x22, 0xe8, 0x01, 0x0a, 0x13, 0x48, 0x65, 0x6c, 0x6d, 0x43, 0x6f,
-	0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a,
-	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x49, 0x0a,
-	0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x2b, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
-	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x52,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x09, 0x72,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x6f,
-	0x75, 0x72, 0x63, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c,
-	0x22, 0x7a, 0x0a, 0x12, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
-	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
-	0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c,
-	0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x2a, 0x4e, 0x0a, 0x05,
-	0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e,
-	0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x45, 0x43, 0x4f, 0x4e, 0x43, 0x49, 0x4c, 0x49, 0x4e,
-	0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44,
-	0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x12, 0x0c,
-	0x0a, 0x08, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x04, 0x42, 0x20, 0x5a, 0x1e,
-	0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x6f, 0x70, 0x65,
-	0x72, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x52, 0x04,
+	0x72, 0x65, 0x70, 0x6f, 0x12, 0x2e, 0x0a, 0x12, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x47, 0x6c,
+	0x6f, 0x62, 0x61, 0x6c, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x12, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x56, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x12, 0x36, 0x0a, 0x16, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x12, 0x4e, 0x0a, 0x0c, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x6c, 0x75,
+	0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x46, 0x0a, 0x0b, 0x63, 0x68, 0x61, 0x72, 0x74, 0x53, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x70, 0x6c, 0x75, 0x6d,
+	0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52,
+	0x0b, 0x63, 0x68, 0x61, 0x72, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x2a, 0x0a, 0x10,
+	0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x61,
+	0x6c, 0x69, 0x64, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x30, 0x0a, 0x13, 0x61, 0x70, 0x70, 0x6c,
+	0x79, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x18,
+	0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x13, 0x61, 0x70, 0x70, 0x6c, 0x79, 0x53, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x52, 0x0a, 0x0f, 0x73, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0f, 0x73,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1c,
+	0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x08,
+	0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x73, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x24,
+	0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e,
+	0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x4f, 0x76, 0x65,
+	0x72, 0x6c, 0x61, 0x79, 0x52, 0x08, 0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x73, 0x22, 0x4f,
+	0x0a, 0x0f, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x49, 0x6e, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0c, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x22,
+	0x9a, 0x01, 0x0a, 0x0b, 0x48, 0x65, 0x6c, 0x6d, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x12,
+	0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b,
+	0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x43, 0x0a, 0x07, 0x70, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x29, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61,
+	0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x50, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x07, 0x70, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x22, 0x54, 0x0a, 0x10,
+	0x48, 0x65, 0x6c, 0x6d, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x50, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x70, 0x61, 0x74, 0x68, 0x12, 0x2c, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x22, 0xae, 0x02, 0x0a, 0x08, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65, 0x70, 0x6f, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x46, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52,
+	0x65, 0x66, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61,
+	0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68,
+	0x61, 0x31, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66, 0x12, 0x34, 0x0a,
+	0x15, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x53, 0x6b, 0x69, 0x70, 0x54, 0x4c, 0x53,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x15, 0x69, 0x6e,
+	0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x53, 0x6b, 0x69, 0x70, 0x54, 0x4c, 0x53, 0x56, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x12, 0x2e, 0x0a, 0x12, 0x70, 0x61, 0x73, 0x73, 0x43, 0x72, 0x65, 0x64, 0x65,
+	0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x41, 0x6c, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x12, 0x70, 0x61, 0x73, 0x73, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73,
+	0x41, 0x6c, 0x6c, 0x12, 0x4e, 0x0a, 0x0c, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x6c, 0x75, 0x6d,
+	0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x43, 0x68, 0x61, 0x72, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x22, 0x25, 0x0a, 0x0f, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66,
+	0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0xfe, 0x01, 0x0a, 0x0b, 0x43,
+	0x68, 0x61, 0x72, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x52,
+	0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x52, 0x65, 0x66, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x43,
+	0x68, 0x61, 0x72, 0x74, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x66, 0x65, 0x72,
+	0x65, 0x6e, 0x63, 0x65, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x4d, 0x61, 0x70, 0x52,
+	0x65, 0x66, 0x12, 0x4c, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70,
+	0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e,
+	0x43, 0x68, 0x61, 0x72, 0x74, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65, 0x66, 0x65,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66,
+	0x12, 0x39, 0x0a, 0x03, 0x67, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e,
+	0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76,
+	0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x47, 0x69, 0x74, 0x43, 0x68, 0x61, 0x72, 0x74,
+	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x52, 0x03, 0x67, 0x69, 0x74, 0x22, 0x90, 0x01, 0x0a, 0x0e,
+	0x47, 0x69, 0x74, 0x43, 0x68, 0x61, 0x72, 0x74, 0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x10,
+	0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c,
+	0x12, 0x10, 0x0a, 0x03, 0x72, 0x65, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x72,
+	0x65, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x46, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x6c, 0x75, 0x6d,
+	0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66, 0x22, 0x3d,
+	0x0a, 0x15, 0x43, 0x68, 0x61, 0x72, 0x74, 0x41, 0x72, 0x63, 0x68, 0x69, 0x76, 0x65, 0x52, 0x65,
+	0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x22, 0x6f, 0x0a,
+	0x11, 0x43, 0x68, 0x61, 0x72, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x46, 0x0a, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x52, 0x65, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x28, 0x2e, 0x70, 0x6c, 0x75, 0x6d,
+	0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70,
+	0x68, 0x61, 0x31, 0x2e, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65,
+	0x6e, 0x63, 0x65, 0x52, 0x09, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x52, 0x65, 0x66, 0x22, 0x93,
+	0x01, 0x0a, 0x0d, 0x48, 0x65, 0x6c, 0x6d, 0x41, 0x70, 0x70, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x34, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x1e, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x50, 0x68, 0x61, 0x73, 0x65, 0x52,
+	0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x4c, 0x0a, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e,
+	0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x70, 0x6c, 0x75,
+	0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c,
+	0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65,
+	0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x0a, 0x63, 0x6f, 0x6d, 0x70, 0x6f, 0x6e,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0x9c, 0x06, 0x0a, 0x13, 0x48, 0x65, 0x6c, 0x6d, 0x43, 0x6f, 0x6d,
+	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x49, 0x0a, 0x09,
+	0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2b, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x09, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0e, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x73, 0x48, 0x61, 0x73, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x73, 0x48, 0x61, 0x73, 0x68, 0x12, 0x41, 0x0a, 0x0f, 0x65, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x53, 0x74, 0x72, 0x75, 0x63, 0x74, 0x52, 0x0f, 0x65, 0x66, 0x66, 0x65, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x12, 0x62, 0x0a, 0x0c, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x73, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x3e, 0x2e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f,
+	0x72, 0x2e, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61, 0x31, 0x2e, 0x48, 0x65, 0x6c, 0x6d, 0x43,
+	0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e, 0x56,
+	0x61, 0x6c, 0x75, 0x65, 0x73, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0c, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x4c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x2a,
+	0x0a, 0x10, 0x69, 0x6e, 0x6a, 0x65, 0x63, 0x74, 0x65, 0x64, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c,
+	0x74, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x69, 0x6e, 0x6a, 0x65, 0x63, 0x74,
+	0x65, 0x64, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x70, 0x72,
+	0x75, 0x6e, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x70, 0x72, 0x75, 0x6e, 0x65, 0x64, 0x50, 0x61, 0x74, 0x68, 0x73, 0x12, 0x24, 0x0a, 0x0d,
+	0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x52, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x52, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x22, 0x0a, 0x0c, 0x63, 0x68, 0x61, 0x72, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x68, 0x61, 0x72, 0x74, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70,
+	0x61, 0x63, 0x65, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73,
+	0x70, 0x61, 0x63, 0x65, 0x12, 0x2c, 0x0a, 0x11, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65,
+	0x64, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x79, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x11, 0x75, 0x6e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x6c, 0x61,
+	0x79, 0x73, 0x1a, 0x3f, 0x0a, 0x11, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x73, 0x4c, 0x61, 0x79, 0x65,
+	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0x7a, 0x0a, 0x12, 0x48, 0x65, 0x6c, 0x6d, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x61, 0x70, 0x69,
+	0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x61,
+	0x70, 0x69, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x2a,
+	0x4e, 0x0a, 0x05, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x55, 0x4e, 0x4b, 0x4e,
+	0x4f, 0x57, 0x4e, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x52, 0x45, 0x43, 0x4f, 0x4e, 0x43, 0x49,
+	0x4c, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45,
+	0x44, 0x45, 0x44, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10,
+	0x03, 0x12, 0x0c, 0x0a, 0x08, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x04, 0x42,
+	0x20, 0x5a, 0x1e, 0x70, 0x6c, 0x75, 0x6d, 0x61, 0x2e, 0x69, 0x6f, 0x2f, 0x61, 0x70, 0x69, 0x2f,
+	0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x2f, 0x76, 0x31, 0x61, 0x6c, 0x70, 0x68, 0x61,
+	0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -610,31 +1676,62 @@ func file_operator_v1alpha1_helmapp_proto_rawDescGZIP() []byte {
 }
 
 var file_operator_v1alpha1_helmapp_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_operator_v1alpha1_helmapp_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_operator_v1alpha1_helmapp_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_operator_v1alpha1_helmapp_proto_goTypes = []interface{}{
-	(Phase)(0),                  // 0: pluma.operator.v1alpha1.Phase
-	(*HelmAppSpec)(nil),         // 1: pluma.operator.v1alpha1.HelmAppSpec
-	(*HelmComponent)(nil),       // 2: pluma.operator.v1alpha1.HelmComponent
-	(*HelmRepo)(nil),            // 3: pluma.operator.v1alpha1.HelmRepo
-	(*HelmAppStatus)(nil),       // 4: pluma.operator.v1alpha1.HelmAppStatus
-	(*HelmComponentStatus)(nil), // 5: pluma.operator.v1alpha1.HelmComponentStatus
-	(*HelmResourceStatus)(nil),  // 6: pluma.operator.v1alpha1.HelmResourceStatus
-	(*structpb.Struct)(nil),     // 7: google.protobuf.Struct
+	(Phase)(0),                    // 0: pluma.operator.v1alpha1.Phase
+	(*HelmAppSpec)(nil),           // 1: pluma.operator.v1alpha1.HelmAppSpec
+	(*EffectiveValues)(nil),       // 2: pluma.operator.v1alpha1.EffectiveValues
+	(*ValuesTemplating)(nil),      // 3: pluma.operator.v1alpha1.ValuesTemplating
+	(*HelmComponent)(nil),         // 4: pluma.operator.v1alpha1.HelmComponent
+	(*SchemaInference)(nil),       // 5: pluma.operator.v1alpha1.SchemaInference
+	(*HelmOverlay)(nil),           // 6: pluma.operator.v1alpha1.HelmOverlay
+	(*HelmOverlayPatch)(nil),      // 7: pluma.operator.v1alpha1.HelmOverlayPatch
+	(*HelmRepo)(nil),              // 8: pluma.operator.v1alpha1.HelmRepo
+	(*SecretReference)(nil),       // 9: pluma.operator.v1alpha1.SecretReference
+	(*ChartSource)(nil),           // 10: pluma.operator.v1alpha1.ChartSource
+	(*GitChartSource)(nil),        // 11: pluma.operator.v1alpha1.GitChartSource
+	(*ChartArchiveReference)(nil), // 12: pluma.operator.v1alpha1.ChartArchiveReference
+	(*ChartVerification)(nil),     // 13: pluma.operator.v1alpha1.ChartVerification
+	(*HelmAppStatus)(nil),         // 14: pluma.operator.v1alpha1.HelmAppStatus
+	(*HelmComponentStatus)(nil),   // 15: pluma.operator.v1alpha1.HelmComponentStatus
+	(*HelmResourceStatus)(nil),    // 16: pluma.operator.v1alpha1.HelmResourceStatus
+	nil,                           // 17: pluma.operator.v1alpha1.HelmAppSpec.MergeStrategiesEntry
+	nil,                           // 18: pluma.operator.v1alpha1.HelmComponentStatus.ValuesLayersEntry
+	(*structpb.Struct)(nil),       // 19: google.protobuf.Struct
+	(*structpb.Value)(nil),        // 20: google.protobuf.Value
 }
 var file_operator_v1alpha1_helmapp_proto_depIdxs = []int32{
-	2, // 0: pluma.operator.v1alpha1.HelmAppSpec.components:type_name -> pluma.operator.v1alpha1.HelmComponent
-	7, // 1: pluma.operator.v1alpha1.HelmAppSpec.globalValues:type_name -> google.protobuf.Struct
-	3, // 2: pluma.operator.v1alpha1.HelmAppSpec.repo:type_name -> pluma.operator.v1alpha1.HelmRepo
-	7, // 3: pluma.operator.v1alpha1.HelmComponent.componentValues:type_name -> google.protobuf.Struct
-	3, // 4: pluma.operator.v1alpha1.HelmComponent.repo:type_name -> pluma.operator.v1alpha1.HelmRepo
-	0, // 5: pluma.operator.v1alpha1.HelmAppStatus.phase:type_name -> pluma.operator.v1alpha1.Phase
-	5, // 6: pluma.operator.v1alpha1.HelmAppStatus.components:type_name -> pluma.operator.v1alpha1.HelmComponentStatus
-	6, // 7: pluma.operator.v1alpha1.HelmComponentStatus.resources:type_name -> pluma.operator.v1alpha1.HelmResourceStatus
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	4,  // 0: pluma.operator.v1alpha1.HelmAppSpec.components:type_name -> pluma.operator.v1alpha1.HelmComponent
+	19, // 1: pluma.operator.v1alpha1.HelmAppSpec.globalValues:type_name -> google.protobuf.Struct
+	8,  // 2: pluma.operator.v1alpha1.HelmAppSpec.repo:type_name -> pluma.operator.v1alpha1.HelmRepo
+	3,  // 3: pluma.operator.v1alpha1.HelmAppSpec.valuesTemplating:type_name -> pluma.operator.v1alpha1.ValuesTemplating
+	17, // 4: pluma.operator.v1alpha1.HelmAppSpec.mergeStrategies:type_name -> pluma.operator.v1alpha1.HelmAppSpec.MergeStrategiesEntry
+	2,  // 5: pluma.operator.v1alpha1.HelmAppSpec.effectiveValues:type_name -> pluma.operator.v1alpha1.EffectiveValues
+	19, // 6: pluma.operator.v1alpha1.HelmComponent.componentValues:type_name -> google.protobuf.Struct
+	8,  // 7: pluma.operator.v1alpha1.HelmComponent.repo:type_name -> pluma.operator.v1alpha1.HelmRepo
+	13, // 8: pluma.operator.v1alpha1.HelmComponent.verification:type_name -> pluma.operator.v1alpha1.ChartVerification
+	10, // 9: pluma.operator.v1alpha1.HelmComponent.chartSource:type_name -> pluma.operator.v1alpha1.ChartSource
+	5,  // 10: pluma.operator.v1alpha1.HelmComponent.schemaInference:type_name -> pluma.operator.v1alpha1.SchemaInference
+	6,  // 11: pluma.operator.v1alpha1.HelmComponent.overlays:type_name -> pluma.operator.v1alpha1.HelmOverlay
+	7,  // 12: pluma.operator.v1alpha1.HelmOverlay.patches:type_name -> pluma.operator.v1alpha1.HelmOverlayPatch
+	20, // 13: pluma.operator.v1alpha1.HelmOverlayPatch.value:type_name -> google.protobuf.Value
+	9,  // 14: pluma.operator.v1alpha1.HelmRepo.secretRef:type_name -> pluma.operator.v1alpha1.SecretReference
+	13, // 15: pluma.operator.v1alpha1.HelmRepo.verification:type_name -> pluma.operator.v1alpha1.ChartVerification
+	12, // 16: pluma.operator.v1alpha1.ChartSource.configMapRef:type_name -> pluma.operator.v1alpha1.ChartArchiveReference
+	12, // 17: pluma.operator.v1alpha1.ChartSource.secretRef:type_name -> pluma.operator.v1alpha1.ChartArchiveReference
+	11, // 18: pluma.operator.v1alpha1.ChartSource.git:type_name -> pluma.operator.v1alpha1.GitChartSource
+	9,  // 19: pluma.operator.v1alpha1.GitChartSource.secretRef:type_name -> pluma.operator.v1alpha1.SecretReference
+	9,  // 20: pluma.operator.v1alpha1.ChartVerification.secretRef:type_name -> pluma.operator.v1alpha1.SecretReference
+	0,  // 21: pluma.operator.v1alpha1.HelmAppStatus.phase:type_name -> pluma.operator.v1alpha1.Phase
+	15, // 22: pluma.operator.v1alpha1.HelmAppStatus.components:type_name -> pluma.operator.v1alpha1.HelmComponentStatus
+	16, // 23: pluma.operator.v1alpha1.HelmComponentStatus.resources:type_name -> pluma.operator.v1alpha1.HelmResourceStatus
+	19, // 24: pluma.operator.v1alpha1.HelmComponentStatus.effectiveValues:type_name -> google.protobuf.Struct
+	18, // 25: pluma.operator.v1alpha1.HelmComponentStatus.valuesLayers:type_name -> pluma.operator.v1alpha1.HelmComponentStatus.ValuesLayersEntry
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_operator_v1alpha1_helmapp_proto_init() }
@@ -656,7 +1753,7 @@ func file_operator_v1alpha1_helmapp_proto_init() {
 			}
 		}
 		file_operator_v1alpha1_helmapp_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HelmComponent); i {
+			switch v := v.(*EffectiveValues); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -668,7 +1765,7 @@ func file_operator_v1alpha1_helmapp_proto_init() {
 			}
 		}
 		file_operator_v1alpha1_helmapp_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HelmRepo); i {
+			switch v := v.(*ValuesTemplating); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -680,7 +1777,7 @@ func file_operator_v1alpha1_helmapp_proto_init() {
 			}
 		}
 		file_operator_v1alpha1_helmapp_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HelmAppStatus); i {
+			switch v := v.(*HelmComponent); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -692,7 +1789,7 @@ func file_operator_v1alpha1_helmapp_proto_init() {
 			}
 		}
 		file_operator_v1alpha1_helmapp_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*HelmComponentStatus); i {
+			switch v := v.(*SchemaInference); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -704,6 +1801,126 @@ func file_operator_v1alpha1_helmapp_proto_init() {
 			}
 		}
 		file_operator_v1alpha1_helmapp_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HelmOverlay); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HelmOverlayPatch); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HelmRepo); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SecretReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChartSource); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GitChartSource); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChartArchiveReference); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ChartVerification); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HelmAppStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*HelmComponentStatus); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_operator_v1alpha1_helmapp_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*HelmResourceStatus); i {
 			case 0:
 				return &v.state
@@ -722,7 +1939,7 @@ func file_operator_v1alpha1_helmapp_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_operator_v1alpha1_helmapp_proto_rawDesc,
 			NumEnums:      1,
-			NumMessages:   6,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
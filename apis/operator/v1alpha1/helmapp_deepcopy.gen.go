@@ -26,6 +26,48 @@ func (in *HelmAppSpec) DeepCopyInterface() interface{} {
 	return in.DeepCopy()
 }
 
+// DeepCopyInto supports using EffectiveValues within kubernetes types, where deepcopy-gen is used.
+func (in *EffectiveValues) DeepCopyInto(out *EffectiveValues) {
+	p := proto.Clone(in).(*EffectiveValues)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveValues. Required by controller-gen.
+func (in *EffectiveValues) DeepCopy() *EffectiveValues {
+	if in == nil {
+		return nil
+	}
+	out := new(EffectiveValues)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new EffectiveValues. Required by controller-gen.
+func (in *EffectiveValues) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using ValuesTemplating within kubernetes types, where deepcopy-gen is used.
+func (in *ValuesTemplating) DeepCopyInto(out *ValuesTemplating) {
+	p := proto.Clone(in).(*ValuesTemplating)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValuesTemplating. Required by controller-gen.
+func (in *ValuesTemplating) DeepCopy() *ValuesTemplating {
+	if in == nil {
+		return nil
+	}
+	out := new(ValuesTemplating)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new ValuesTemplating. Required by controller-gen.
+func (in *ValuesTemplating) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
 // DeepCopyInto supports using HelmComponent within kubernetes types, where deepcopy-gen is used.
 func (in *HelmComponent) DeepCopyInto(out *HelmComponent) {
 	p := proto.Clone(in).(*HelmComponent)
@@ -47,6 +89,69 @@ func (in *HelmComponent) DeepCopyInterface() interface{} {
 	return in.DeepCopy()
 }
 
+// DeepCopyInto supports using SchemaInference within kubernetes types, where deepcopy-gen is used.
+func (in *SchemaInference) DeepCopyInto(out *SchemaInference) {
+	p := proto.Clone(in).(*SchemaInference)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaInference. Required by controller-gen.
+func (in *SchemaInference) DeepCopy() *SchemaInference {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaInference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new SchemaInference. Required by controller-gen.
+func (in *SchemaInference) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using HelmOverlay within kubernetes types, where deepcopy-gen is used.
+func (in *HelmOverlay) DeepCopyInto(out *HelmOverlay) {
+	p := proto.Clone(in).(*HelmOverlay)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmOverlay. Required by controller-gen.
+func (in *HelmOverlay) DeepCopy() *HelmOverlay {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmOverlay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new HelmOverlay. Required by controller-gen.
+func (in *HelmOverlay) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using HelmOverlayPatch within kubernetes types, where deepcopy-gen is used.
+func (in *HelmOverlayPatch) DeepCopyInto(out *HelmOverlayPatch) {
+	p := proto.Clone(in).(*HelmOverlayPatch)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmOverlayPatch. Required by controller-gen.
+func (in *HelmOverlayPatch) DeepCopy() *HelmOverlayPatch {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmOverlayPatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new HelmOverlayPatch. Required by controller-gen.
+func (in *HelmOverlayPatch) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
 // DeepCopyInto supports using HelmRepo within kubernetes types, where deepcopy-gen is used.
 func (in *HelmRepo) DeepCopyInto(out *HelmRepo) {
 	p := proto.Clone(in).(*HelmRepo)
@@ -68,6 +173,111 @@ func (in *HelmRepo) DeepCopyInterface() interface{} {
 	return in.DeepCopy()
 }
 
+// DeepCopyInto supports using SecretReference within kubernetes types, where deepcopy-gen is used.
+func (in *SecretReference) DeepCopyInto(out *SecretReference) {
+	p := proto.Clone(in).(*SecretReference)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference. Required by controller-gen.
+func (in *SecretReference) DeepCopy() *SecretReference {
+	if in == nil {
+		return nil
+	}
+	out := new(SecretReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new SecretReference. Required by controller-gen.
+func (in *SecretReference) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using ChartSource within kubernetes types, where deepcopy-gen is used.
+func (in *ChartSource) DeepCopyInto(out *ChartSource) {
+	p := proto.Clone(in).(*ChartSource)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartSource. Required by controller-gen.
+func (in *ChartSource) DeepCopy() *ChartSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new ChartSource. Required by controller-gen.
+func (in *ChartSource) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using GitChartSource within kubernetes types, where deepcopy-gen is used.
+func (in *GitChartSource) DeepCopyInto(out *GitChartSource) {
+	p := proto.Clone(in).(*GitChartSource)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitChartSource. Required by controller-gen.
+func (in *GitChartSource) DeepCopy() *GitChartSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GitChartSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new GitChartSource. Required by controller-gen.
+func (in *GitChartSource) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using ChartArchiveReference within kubernetes types, where deepcopy-gen is used.
+func (in *ChartArchiveReference) DeepCopyInto(out *ChartArchiveReference) {
+	p := proto.Clone(in).(*ChartArchiveReference)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartArchiveReference. Required by controller-gen.
+func (in *ChartArchiveReference) DeepCopy() *ChartArchiveReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartArchiveReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new ChartArchiveReference. Required by controller-gen.
+func (in *ChartArchiveReference) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
+// DeepCopyInto supports using ChartVerification within kubernetes types, where deepcopy-gen is used.
+func (in *ChartVerification) DeepCopyInto(out *ChartVerification) {
+	p := proto.Clone(in).(*ChartVerification)
+	*out = *p
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChartVerification. Required by controller-gen.
+func (in *ChartVerification) DeepCopy() *ChartVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ChartVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInterface is an autogenerated deepcopy function, copying the receiver, creating a new ChartVerification. Required by controller-gen.
+func (in *ChartVerification) DeepCopyInterface() interface{} {
+	return in.DeepCopy()
+}
+
 // DeepCopyInto supports using HelmAppStatus within kubernetes types, where deepcopy-gen is used.
 func (in *HelmAppStatus) DeepCopyInto(out *HelmAppStatus) {
 	p := proto.Clone(in).(*HelmAppStatus)
@@ -18,6 +18,28 @@ func (this *HelmAppSpec) UnmarshalJSON(b []byte) error {
 	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
 }
 
+// MarshalJSON is a custom marshaler for EffectiveValues
+func (this *EffectiveValues) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for EffectiveValues
+func (this *EffectiveValues) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for ValuesTemplating
+func (this *ValuesTemplating) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for ValuesTemplating
+func (this *ValuesTemplating) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
 // MarshalJSON is a custom marshaler for HelmComponent
 func (this *HelmComponent) MarshalJSON() ([]byte, error) {
 	str, err := HelmappMarshaler.MarshalToString(this)
@@ -29,6 +51,39 @@ func (this *HelmComponent) UnmarshalJSON(b []byte) error {
 	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
 }
 
+// MarshalJSON is a custom marshaler for SchemaInference
+func (this *SchemaInference) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for SchemaInference
+func (this *SchemaInference) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for HelmOverlay
+func (this *HelmOverlay) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for HelmOverlay
+func (this *HelmOverlay) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for HelmOverlayPatch
+func (this *HelmOverlayPatch) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for HelmOverlayPatch
+func (this *HelmOverlayPatch) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
 // MarshalJSON is a custom marshaler for HelmRepo
 func (this *HelmRepo) MarshalJSON() ([]byte, error) {
 	str, err := HelmappMarshaler.MarshalToString(this)
@@ -40,6 +95,61 @@ func (this *HelmRepo) UnmarshalJSON(b []byte) error {
 	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
 }
 
+// MarshalJSON is a custom marshaler for SecretReference
+func (this *SecretReference) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for SecretReference
+func (this *SecretReference) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for ChartSource
+func (this *ChartSource) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for ChartSource
+func (this *ChartSource) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for GitChartSource
+func (this *GitChartSource) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for GitChartSource
+func (this *GitChartSource) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for ChartArchiveReference
+func (this *ChartArchiveReference) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for ChartArchiveReference
+func (this *ChartArchiveReference) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
+// MarshalJSON is a custom marshaler for ChartVerification
+func (this *ChartVerification) MarshalJSON() ([]byte, error) {
+	str, err := HelmappMarshaler.MarshalToString(this)
+	return []byte(str), err
+}
+
+// UnmarshalJSON is a custom unmarshaler for ChartVerification
+func (this *ChartVerification) UnmarshalJSON(b []byte) error {
+	return HelmappUnmarshaler.Unmarshal(bytes.NewReader(b), this)
+}
+
 // MarshalJSON is a custom marshaler for HelmAppStatus
 func (this *HelmAppStatus) MarshalJSON() ([]byte, error) {
 	str, err := HelmappMarshaler.MarshalToString(this)
@@ -18,6 +18,19 @@ export type HelmAppSpec = {
   components?: HelmComponent[]
   globalValues?: GoogleProtobufStruct.Struct
   repo?: HelmRepo
+  valuesTemplating?: ValuesTemplating
+  redactPaths?: string[]
+  mergeStrategies?: {[key: string]: string}
+  effectiveValues?: EffectiveValues
+}
+
+export type EffectiveValues = {
+  target?: string
+}
+
+export type ValuesTemplating = {
+  enabled?: boolean
+  configMaps?: string[]
 }
 
 export type HelmComponent = {
@@ -28,11 +41,68 @@ export type HelmComponent = {
   repo?: HelmRepo
   ignoreGlobalValues?: boolean
   enableSchemaValidation?: boolean
+  digest?: string
+  verification?: ChartVerification
+  chartSource?: ChartSource
+  schemaValidation?: string
+  applySchemaDefaults?: boolean
+  schemaInference?: SchemaInference
+  namespace?: string
+  overlays?: HelmOverlay[]
+}
+
+export type SchemaInference = {
+  enabled?: boolean
+  allowedPaths?: string[]
+}
+
+export type HelmOverlay = {
+  apiVersion?: string
+  kind?: string
+  name?: string
+  patches?: HelmOverlayPatch[]
+}
+
+export type HelmOverlayPatch = {
+  path?: string
+  value?: GoogleProtobufStruct.Value
 }
 
 export type HelmRepo = {
   name?: string
   url?: string
+  secretRef?: SecretReference
+  insecureSkipTLSVerify?: boolean
+  passCredentialsAll?: boolean
+  verification?: ChartVerification
+}
+
+export type SecretReference = {
+  name?: string
+}
+
+export type ChartSource = {
+  path?: string
+  configMapRef?: ChartArchiveReference
+  secretRef?: ChartArchiveReference
+  git?: GitChartSource
+}
+
+export type GitChartSource = {
+  url?: string
+  ref?: string
+  path?: string
+  secretRef?: SecretReference
+}
+
+export type ChartArchiveReference = {
+  name?: string
+  key?: string
+}
+
+export type ChartVerification = {
+  mode?: string
+  secretRef?: SecretReference
 }
 
 export type HelmAppStatus = {
@@ -47,6 +117,18 @@ export type HelmComponentStatus = {
   version?: string
   resources?: HelmResourceStatus[]
   resourcesTotal?: number
+  digest?: string
+  reason?: string
+  commit?: string
+  valuesHash?: string
+  effectiveValues?: GoogleProtobufStruct.Struct
+  valuesLayers?: {[key: string]: string}
+  injectedDefaults?: string[]
+  prunedPaths?: string[]
+  upgradeReason?: string
+  chartVersion?: string
+  namespace?: string
+  unmatchedOverlays?: string[]
 }
 
 export type HelmResourceStatus = {
@@ -42,6 +42,9 @@ func main() {
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
 	flag.StringVar(&config.GlobalConfig.ProfilesDir, "profiles-dir", "./istio/profiles", "Directory containing Istio profiles")
+	flag.StringVar(&config.GlobalConfig.IstioVersion, "istio-version", istio.DefaultIstioVersion,
+		"Version of the Istio charts of the IstioOperators whose tag is not a version.")
+	flag.BoolVar(&config.GlobalConfig.Debug, "helm-debug", false, "Enable the debug logs of Helm, sensitive values are masked.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -65,8 +68,10 @@ func main() {
 	}
 
 	if err = (&controller.HelmAppReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Config:   config.GlobalConfig,
+		Recorder: mgr.GetEventRecorderFor("pluma-operator"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HelmApp")
 		os.Exit(1)
@@ -3,6 +3,10 @@ package config
 // Config holds global configuration for the operator
 type Config struct {
 	ProfilesDir string
+	// IstioVersion is the version of the Istio charts of the IstioOperators which set none
+	IstioVersion string
+	// Debug enables the debug logs of the Helm SDK
+	Debug bool
 }
 
 // GlobalConfig is the global configuration instance
@@ -0,0 +1,147 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"pluma.io/pluma-operator/internal/pkg/redact"
+	"pluma.io/pluma-operator/internal/pkg/tools"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	effectiveValuesTargetConfigMap = "ConfigMap"
+	effectiveValuesTargetStatus    = "Status"
+	// effectiveValuesKey is the key of the values in the ConfigMap
+	effectiveValuesKey = "values.yaml"
+
+	layerGlobalValues    = "globalValues"
+	layerComponentValues = "componentValues"
+	layerSchemaDefaults  = "schemaDefaults"
+)
+
+// effectiveValuesConfigMapName returns the name of the ConfigMap holding the effective values of a component
+func effectiveValuesConfigMapName(helmAppName, componentName string) string {
+	return fmt.Sprintf("%s-%s-values", helmAppName, componentName)
+}
+
+// valuesLayers returns the layers setting each top-level key of values, in merge order.
+// Keys dropped by schema filtering are not in values, keys in neither layer were injected from the schema defaults.
+func valuesLayers(values, globalValues, componentValues map[string]interface{}, ignoreGlobalValues bool) map[string]string {
+	layers := make(map[string]string, len(values))
+	for k := range values {
+		var l []string
+		if _, ok := globalValues[k]; ok && !ignoreGlobalValues {
+			l = append(l, layerGlobalValues)
+		}
+		if _, ok := componentValues[k]; ok {
+			l = append(l, layerComponentValues)
+		}
+		if len(l) == 0 {
+			l = append(l, layerSchemaDefaults)
+		}
+		layers[k] = strings.Join(l, ",")
+	}
+	return layers
+}
+
+// publishEffectiveValues writes the values given to Helm to the target of the HelmApp, sensitive values are masked
+func (r *HelmAppReconciler) publishEffectiveValues(ctx context.Context, helmApp *operatorv1alpha1.HelmApp, componentName string,
+	componentStatus *operatorv1alpha1.HelmComponentStatus, values map[string]interface{}, layers map[string]string, redactor *redact.Redactor) error {
+	target := helmApp.Spec.GetEffectiveValues().GetTarget()
+	if last := getComponentStatus(helmApp, componentName); target != effectiveValuesTargetConfigMap &&
+		last.GetValuesHash() != "" && last.GetEffectiveValues() == nil {
+		// The values were published to a ConfigMap before the target changed
+		if err := r.deleteEffectiveValues(ctx, helmApp, componentName); err != nil {
+			return err
+		}
+	}
+	if target == "" {
+		return nil
+	}
+
+	hash, err := tools.HashValues(values)
+	if err != nil {
+		return fmt.Errorf("failed to hash effective values: %w", err)
+	}
+	componentStatus.ValuesHash = hash
+	masked := redactor.Values(values)
+
+	switch target {
+	case effectiveValuesTargetStatus:
+		s, err := structpb.NewStruct(masked)
+		if err != nil {
+			return fmt.Errorf("failed to convert effective values: %w", err)
+		}
+		componentStatus.EffectiveValues = s
+		componentStatus.ValuesLayers = layers
+		return nil
+	case effectiveValuesTargetConfigMap:
+		data, err := yaml.Marshal(masked)
+		if err != nil {
+			return fmt.Errorf("failed to marshal effective values: %w", err)
+		}
+		layersJSON, err := json.Marshal(layers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal values layers: %w", err)
+		}
+
+		cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+			Name:      effectiveValuesConfigMapName(helmApp.Name, componentName),
+			Namespace: helmApp.Namespace,
+		}}
+		_, err = controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+			if cm.Labels == nil {
+				cm.Labels = map[string]string{}
+			}
+			cm.Labels[constants.ManagedLabel] = constants.ManagedLabelValue
+			cm.Labels[constants.ComponentLabel] = componentName
+			if cm.Annotations == nil {
+				cm.Annotations = map[string]string{}
+			}
+			cm.Annotations[constants.ValuesHashAnnotation] = hash
+			cm.Annotations[constants.ValuesLayersAnnotation] = string(layersJSON)
+			cm.Data = map[string]string{effectiveValuesKey: string(data)}
+			return controllerutil.SetControllerReference(helmApp, cm, r.Scheme)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write effective values to configmap %s: %w", cm.Name, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown effective values target %q", target)
+	}
+}
+
+// keepEffectiveValues copies the effective values published for the last release to the new status
+func keepEffectiveValues(componentStatus, last *operatorv1alpha1.HelmComponentStatus) {
+	componentStatus.ValuesHash = last.GetValuesHash()
+	componentStatus.EffectiveValues = last.GetEffectiveValues()
+	componentStatus.ValuesLayers = last.GetValuesLayers()
+}
+
+// deleteEffectiveValues deletes the ConfigMap of the effective values of a component if the HelmApp owns it
+func (r *HelmAppReconciler) deleteEffectiveValues(ctx context.Context, helmApp *operatorv1alpha1.HelmApp, componentName string) error {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: helmApp.Namespace, Name: effectiveValuesConfigMapName(helmApp.Name, componentName)}
+	if err := r.Get(ctx, key, cm); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if !metav1.IsControlledBy(cm, helmApp) {
+		return nil
+	}
+	if err := r.Delete(ctx, cm); err != nil && !errors2.IsNotFound(err) {
+		return fmt.Errorf("failed to delete effective values configmap %s: %w", key.Name, err)
+	}
+	return nil
+}
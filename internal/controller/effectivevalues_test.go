@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"pluma.io/pluma-operator/internal/pkg/redact"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+func TestValuesLayers(t *testing.T) {
+	globalValues := map[string]interface{}{"global": map[string]interface{}{}, "meshConfig": map[string]interface{}{}}
+	componentValues := map[string]interface{}{"global": map[string]interface{}{}, "pilot": map[string]interface{}{}}
+	values := map[string]interface{}{"global": nil, "meshConfig": nil, "pilot": nil, "revision": nil}
+
+	tests := []struct {
+		name               string
+		ignoreGlobalValues bool
+		expected           map[string]string
+	}{
+		{
+			name: "merged values",
+			expected: map[string]string{
+				"global":     "globalValues,componentValues",
+				"meshConfig": "globalValues",
+				"pilot":      "componentValues",
+				"revision":   "schemaDefaults",
+			},
+		},
+		{
+			name:               "ignore global values",
+			ignoreGlobalValues: true,
+			expected: map[string]string{
+				"global":     "componentValues",
+				"meshConfig": "schemaDefaults",
+				"pilot":      "componentValues",
+				"revision":   "schemaDefaults",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := valuesLayers(values, globalValues, componentValues, tt.ignoreGlobalValues)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("valuesLayers() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHelmAppReconciler_publishEffectiveValues(t *testing.T) {
+	values := map[string]interface{}{
+		"pilot":  map[string]interface{}{"replicaCount": float64(2)},
+		"global": map[string]interface{}{"password": "s3cr3t"},
+	}
+	layers := map[string]string{"pilot": "componentValues", "global": "globalValues"}
+	masked := map[string]interface{}{
+		"pilot":  map[string]interface{}{"replicaCount": float64(2)},
+		"global": map[string]interface{}{"password": redact.Mask},
+	}
+
+	tests := []struct {
+		name            string
+		target          string
+		expectStatus    bool
+		expectCM        bool
+		expectErr       bool
+		expectNoHash    bool
+		publishedBefore bool
+	}{
+		{name: "disabled", expectNoHash: true},
+		{name: "status", target: "Status", expectStatus: true},
+		{name: "configmap", target: "ConfigMap", expectCM: true},
+		{name: "configmap cleaned up", target: "Status", expectStatus: true, publishedBefore: true},
+		{name: "unknown target", target: "Secret", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helmApp := &operatorv1alpha1.HelmApp{
+				ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system", UID: "uid"},
+				Spec: &operatorv1alpha1.HelmAppSpec{
+					EffectiveValues: &operatorv1alpha1.EffectiveValues{Target: tt.target},
+				},
+			}
+			r := newFakeReconciler()
+			ctx := context.Background()
+			if tt.publishedBefore {
+				helmApp.Spec.EffectiveValues.Target = "ConfigMap"
+				if err := r.publishEffectiveValues(ctx, helmApp, "istiod", &operatorv1alpha1.HelmComponentStatus{}, values, layers, redact.New(nil)); err != nil {
+					t.Fatalf("publishEffectiveValues() error = %v", err)
+				}
+				helmApp.Spec.EffectiveValues.Target = tt.target
+				helmApp.Status = &operatorv1alpha1.HelmAppStatus{Components: []*operatorv1alpha1.HelmComponentStatus{
+					{Name: "istiod", ValuesHash: "sha256:previous"},
+				}}
+			}
+
+			status := &operatorv1alpha1.HelmComponentStatus{Name: "istiod"}
+			err := r.publishEffectiveValues(ctx, helmApp, "istiod", status, values, layers, redact.New(nil))
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("publishEffectiveValues() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("publishEffectiveValues() error = %v", err)
+			}
+
+			if (status.ValuesHash == "") != tt.expectNoHash {
+				t.Errorf("publishEffectiveValues() hash = %q", status.ValuesHash)
+			}
+			if tt.expectStatus {
+				if !reflect.DeepEqual(status.EffectiveValues.AsMap(), masked) {
+					t.Errorf("publishEffectiveValues() status values = %v, want %v", status.EffectiveValues.AsMap(), masked)
+				}
+				if !reflect.DeepEqual(status.ValuesLayers, layers) {
+					t.Errorf("publishEffectiveValues() status layers = %v, want %v", status.ValuesLayers, layers)
+				}
+			} else if status.EffectiveValues != nil {
+				t.Errorf("publishEffectiveValues() should not set the status values")
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = r.Get(ctx, client.ObjectKey{Namespace: "istio-system", Name: "istio-istiod-values"}, cm)
+			if !tt.expectCM {
+				if err == nil {
+					t.Errorf("publishEffectiveValues() should not leave a configmap")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to get configmap: %v", err)
+			}
+			got := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(cm.Data[effectiveValuesKey]), &got); err != nil {
+				t.Fatalf("failed to parse configmap values: %v", err)
+			}
+			if !reflect.DeepEqual(got, masked) {
+				t.Errorf("publishEffectiveValues() configmap values = %v, want %v", got, masked)
+			}
+			if cm.Annotations[constants.ValuesHashAnnotation] != status.ValuesHash {
+				t.Errorf("publishEffectiveValues() configmap hash = %q, want %q", cm.Annotations[constants.ValuesHashAnnotation], status.ValuesHash)
+			}
+			if cm.Annotations[constants.ValuesLayersAnnotation] != `{"global":"globalValues","pilot":"componentValues"}` {
+				t.Errorf("publishEffectiveValues() configmap layers = %q", cm.Annotations[constants.ValuesLayersAnnotation])
+			}
+			if !metav1.IsControlledBy(cm, helmApp) {
+				t.Errorf("publishEffectiveValues() configmap should be owned by the HelmApp")
+			}
+		})
+	}
+}
+
+func TestKeepEffectiveValues(t *testing.T) {
+	values, err := structpb.NewStruct(map[string]interface{}{"pilot": map[string]interface{}{"replicaCount": 2}})
+	if err != nil {
+		t.Fatalf("failed to create values: %v", err)
+	}
+	last := &operatorv1alpha1.HelmComponentStatus{
+		ValuesHash:      "sha256:last",
+		EffectiveValues: values,
+		ValuesLayers:    map[string]string{"pilot": "componentValues"},
+	}
+
+	status := &operatorv1alpha1.HelmComponentStatus{}
+	keepEffectiveValues(status, last)
+	if status.ValuesHash != last.ValuesHash || status.EffectiveValues != last.EffectiveValues ||
+		!reflect.DeepEqual(status.ValuesLayers, last.ValuesLayers) {
+		t.Errorf("keepEffectiveValues() = %v, want the values of %v", status, last)
+	}
+
+	status = &operatorv1alpha1.HelmComponentStatus{}
+	keepEffectiveValues(status, nil)
+	if status.ValuesHash != "" || status.EffectiveValues != nil || status.ValuesLayers != nil {
+		t.Errorf("keepEffectiveValues() without a last status = %v, want no values", status)
+	}
+}
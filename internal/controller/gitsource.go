@@ -0,0 +1,264 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/helmpath"
+	corev1 "k8s.io/api/core/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// Keys read from the Secret referenced by GitChartSource.secretRef, on top of the repo credential keys
+	gitSecretIdentityKey   = "identity"
+	gitSecretKnownHostsKey = "known_hosts"
+
+	gitRemoteName = "origin"
+)
+
+var (
+	// gitWorkspace is the directory the Git chart sources are fetched into, one bare repository per url
+	gitWorkspace = helmpath.CachePath("git")
+	// gitWorkspaceMu serializes the fetches into the workspace
+	gitWorkspaceMu sync.Mutex
+)
+
+// loadGitChart fetches the Git repository of the source, loads the chart at the resolved ref
+// and returns it with the commit SHA it was loaded from.
+func (r *HelmAppReconciler) loadGitChart(ctx context.Context, namespace string,
+	source *operatorv1alpha1.GitChartSource) (*chart.Chart, string, error) {
+	auth, cleanup, err := r.newGitAuth(ctx, namespace, source)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load git credentials: %w", err)
+	}
+	defer cleanup()
+
+	gitWorkspaceMu.Lock()
+	defer gitWorkspaceMu.Unlock()
+
+	repo, err := fetchGitRepo(ctx, gitRepoDir(source.GetUrl()), source.GetUrl(), auth)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to fetch %s: %w", source.GetUrl(), err)
+	}
+
+	ref := strings.TrimSpace(source.GetRef())
+	if ref == "" {
+		if ref, err = defaultGitBranch(ctx, repo, auth); err != nil {
+			return nil, "", fmt.Errorf("failed to resolve the default branch of %s: %w", source.GetUrl(), err)
+		}
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve ref %s of %s: %w", ref, source.GetUrl(), err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, "", err
+	}
+
+	lChart, err := loadGitTreeChart(commit, source.GetPath())
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load chart %s at %s: %w", source.GetPath(), commit.Hash, err)
+	}
+	return lChart, commit.Hash.String(), nil
+}
+
+// newGitAuth builds the transport auth of the source from its Secret, the returned cleanup
+// function removes the temporary files it needed.
+func (r *HelmAppReconciler) newGitAuth(ctx context.Context, namespace string,
+	source *operatorv1alpha1.GitChartSource) (transport.AuthMethod, func(), error) {
+	cleanup := func() {}
+	if source.GetSecretRef().GetName() == "" {
+		return nil, cleanup, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: source.GetSecretRef().GetName()}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, cleanup, fmt.Errorf("failed to get secret %s: %w", key, err)
+	}
+
+	username := string(secret.Data[repoSecretUsernameKey])
+	if identity, ok := secret.Data[gitSecretIdentityKey]; ok {
+		u, err := url.Parse(source.GetUrl())
+		if err == nil && u.User != nil && username == "" {
+			username = u.User.Username()
+		}
+		if username == "" {
+			username = "git"
+		}
+		auth, err := gitssh.NewPublicKeys(username, identity, string(secret.Data[repoSecretPasswordKey]))
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("invalid identity in secret %s: %w", key, err)
+		}
+
+		// Host keys are verified against the known_hosts of the secret
+		knownHosts, ok := secret.Data[gitSecretKnownHostsKey]
+		if !ok || len(knownHosts) == 0 {
+			return nil, cleanup, fmt.Errorf("secret %s has no %s", key, gitSecretKnownHostsKey)
+		}
+		f, err := os.CreateTemp("", "known_hosts-*")
+		if err != nil {
+			return nil, cleanup, err
+		}
+		cleanup = func() {
+			if err := os.Remove(f.Name()); err != nil {
+				warning(ctx, "failed to remove %s: %v", f.Name(), err)
+			}
+		}
+		if _, err := f.Write(knownHosts); err != nil {
+			f.Close()
+			return nil, cleanup, err
+		}
+		if err := f.Close(); err != nil {
+			return nil, cleanup, err
+		}
+		if auth.HostKeyCallback, err = gitssh.NewKnownHostsCallback(f.Name()); err != nil {
+			return nil, cleanup, fmt.Errorf("invalid %s in secret %s: %w", gitSecretKnownHostsKey, key, err)
+		}
+		return auth, cleanup, nil
+	}
+
+	if token := string(secret.Data[repoSecretTokenKey]); token != "" {
+		if username == "" {
+			// Git hosts ignore the username of token auth, but it must not be empty
+			username = "git"
+		}
+		return &githttp.BasicAuth{Username: username, Password: token}, cleanup, nil
+	}
+	if username != "" {
+		return &githttp.BasicAuth{Username: username, Password: string(secret.Data[repoSecretPasswordKey])}, cleanup, nil
+	}
+	return nil, cleanup, nil
+}
+
+// gitRepoDir returns the workspace directory of the repository url
+func gitRepoDir(repoURL string) string {
+	return filepath.Join(gitWorkspace, fmt.Sprintf("%x", sha256.Sum256([]byte(repoURL)))[:16])
+}
+
+// fetchGitRepo fetches all branches and tags of the url into a bare repository in dir
+func fetchGitRepo(ctx context.Context, dir, repoURL string, auth transport.AuthMethod) (*git.Repository, error) {
+	repo, err := git.PlainOpen(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		repo, err = git.PlainInit(dir, true)
+		if err == nil {
+			_, err = repo.CreateRemote(&config.RemoteConfig{Name: gitRemoteName, URLs: []string{repoURL}})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: gitRemoteName,
+		RefSpecs: []config.RefSpec{
+			"+refs/heads/*:refs/heads/*",
+			"+refs/tags/*:refs/tags/*",
+		},
+		Auth:  auth,
+		Tags:  git.NoTags,
+		Force: true,
+		Prune: true,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// defaultGitBranch returns the branch the HEAD of the remote points to
+func defaultGitBranch(ctx context.Context, repo *git.Repository, auth transport.AuthMethod) (string, error) {
+	remote, err := repo.Remote(gitRemoteName)
+	if err != nil {
+		return "", err
+	}
+	refs, err := remote.ListContext(ctx, &git.ListOptions{Auth: auth})
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range refs {
+		if ref.Name() != plumbing.HEAD {
+			continue
+		}
+		if ref.Type() == plumbing.SymbolicReference {
+			return ref.Target().Short(), nil
+		}
+		return ref.Hash().String(), nil
+	}
+	return "", fmt.Errorf("remote has no HEAD")
+}
+
+// loadGitTreeChart checks the chart directory of the commit out into a temporary directory and loads it,
+// so that .helmignore and the other rules of a chart directory apply.
+func loadGitTreeChart(commit *object.Commit, chartPath string) (*chart.Chart, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	chartPath = strings.Trim(path.Clean("/"+chartPath), "/")
+	if chartPath != "" {
+		if tree, err = tree.Tree(chartPath); err != nil {
+			return nil, err
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "helm-git-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if f.Mode == filemode.Symlink || f.Mode == filemode.Submodule {
+			return nil
+		}
+		return writeGitFile(filepath.Join(dir, filepath.FromSlash(f.Name)), f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadDir(dir)
+}
+
+func writeGitFile(name string, f *object.File) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	r, err := f.Reader()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	out, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,279 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+// testGitRepo is a local bare repository with a working copy to push commits from
+type testGitRepo struct {
+	t    *testing.T
+	url  string
+	work *git.Repository
+	dir  string
+}
+
+func newTestGitRepo(t *testing.T) *testGitRepo {
+	t.Helper()
+
+	bare := t.TempDir()
+	bareRepo, err := git.PlainInit(bare, true)
+	if err != nil {
+		t.Fatalf("failed to init bare repo: %v", err)
+	}
+	if err := bareRepo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))); err != nil {
+		t.Fatalf("failed to set HEAD: %v", err)
+	}
+
+	dir := t.TempDir()
+	work, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init repo: %v", err)
+	}
+	if _, err := work.CreateRemote(&config.RemoteConfig{Name: gitRemoteName, URLs: []string{bare}}); err != nil {
+		t.Fatalf("failed to create remote: %v", err)
+	}
+	return &testGitRepo{t: t, url: bare, work: work, dir: dir}
+}
+
+// commitChart commits a chart with the version under charts/demo, pushes it to the branch and returns the commit SHA
+func (g *testGitRepo) commitChart(branch, version, tag string) string {
+	g.t.Helper()
+
+	files := map[string]string{
+		"charts/demo/Chart.yaml":      "apiVersion: v2\nname: demo\nversion: " + version + "\n",
+		"charts/demo/values.yaml":     "replicas: 1\n",
+		"charts/demo/.helmignore":     "*.md\n",
+		"charts/demo/NOTES.md":        "ignored\n",
+		"charts/demo/templates/.keep": "",
+	}
+	for name, content := range files {
+		p := filepath.Join(g.dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			g.t.Fatalf("failed to create dir: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			g.t.Fatalf("failed to write file: %v", err)
+		}
+	}
+
+	wt, err := g.work.Worktree()
+	if err != nil {
+		g.t.Fatalf("failed to get worktree: %v", err)
+	}
+	if err := wt.AddGlob("."); err != nil {
+		g.t.Fatalf("failed to add files: %v", err)
+	}
+	hash, err := wt.Commit("demo "+version, &git.CommitOptions{
+		Author: &object.Signature{Name: "pluma", Email: "pluma@example.com", When: time.Now()},
+	})
+	if err != nil {
+		g.t.Fatalf("failed to commit: %v", err)
+	}
+
+	head, err := g.work.Head()
+	if err != nil {
+		g.t.Fatalf("failed to get HEAD: %v", err)
+	}
+	refSpecs := []config.RefSpec{config.RefSpec("+" + head.Name().String() + ":refs/heads/" + branch)}
+	if tag != "" {
+		if _, err := g.work.CreateTag(tag, hash, nil); err != nil {
+			g.t.Fatalf("failed to tag: %v", err)
+		}
+		refSpecs = append(refSpecs, config.RefSpec("refs/tags/"+tag+":refs/tags/"+tag))
+	}
+	if err := g.work.Push(&git.PushOptions{RemoteName: gitRemoteName, RefSpecs: refSpecs}); err != nil {
+		g.t.Fatalf("failed to push: %v", err)
+	}
+	return hash.String()
+}
+
+func TestHelmAppReconciler_loadGitChart(t *testing.T) {
+	workspace := gitWorkspace
+	gitWorkspace = t.TempDir()
+	defer func() { gitWorkspace = workspace }()
+
+	repo := newTestGitRepo(t)
+	first := repo.commitChart("main", "0.1.0", "v0.1.0")
+	release := repo.commitChart("release-0.2", "0.2.0", "")
+
+	r := newFakeReconciler()
+
+	tests := []struct {
+		name            string
+		source          *operatorv1alpha1.GitChartSource
+		expectedCommit  string
+		expectedVersion string
+		expectErr       bool
+	}{
+		{
+			name:            "default branch",
+			source:          &operatorv1alpha1.GitChartSource{Url: repo.url, Path: "charts/demo"},
+			expectedCommit:  first,
+			expectedVersion: "0.1.0",
+		},
+		{
+			name:            "branch",
+			source:          &operatorv1alpha1.GitChartSource{Url: repo.url, Ref: "release-0.2", Path: "charts/demo"},
+			expectedCommit:  release,
+			expectedVersion: "0.2.0",
+		},
+		{
+			name:            "tag",
+			source:          &operatorv1alpha1.GitChartSource{Url: repo.url, Ref: "v0.1.0", Path: "/charts/demo/"},
+			expectedCommit:  first,
+			expectedVersion: "0.1.0",
+		},
+		{
+			name:            "commit",
+			source:          &operatorv1alpha1.GitChartSource{Url: repo.url, Ref: first, Path: "charts/demo"},
+			expectedCommit:  first,
+			expectedVersion: "0.1.0",
+		},
+		{
+			name:      "unknown ref",
+			source:    &operatorv1alpha1.GitChartSource{Url: repo.url, Ref: "not-found", Path: "charts/demo"},
+			expectErr: true,
+		},
+		{
+			name:      "missing path",
+			source:    &operatorv1alpha1.GitChartSource{Url: repo.url, Path: "charts/not-found"},
+			expectErr: true,
+		},
+		{
+			name:      "missing repository",
+			source:    &operatorv1alpha1.GitChartSource{Url: filepath.Join(t.TempDir(), "not-found"), Path: "charts/demo"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lChart, commit, err := r.loadGitChart(context.Background(), "istio-system", tt.source)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("loadGitChart() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadGitChart() error = %v", err)
+			}
+			if commit != tt.expectedCommit {
+				t.Errorf("loadGitChart() commit = %s, want %s", commit, tt.expectedCommit)
+			}
+			if lChart.Metadata.Version != tt.expectedVersion {
+				t.Errorf("loadGitChart() version = %s, want %s", lChart.Metadata.Version, tt.expectedVersion)
+			}
+			for _, f := range lChart.Raw {
+				if f.Name == "NOTES.md" {
+					t.Errorf("loadGitChart() loaded file ignored by .helmignore")
+				}
+			}
+		})
+	}
+
+	// A new commit on the branch is picked up by the next fetch
+	second := repo.commitChart("main", "0.1.1", "")
+	_, commit, err := r.loadGitChart(context.Background(), "istio-system",
+		&operatorv1alpha1.GitChartSource{Url: repo.url, Ref: "main", Path: "charts/demo"})
+	if err != nil {
+		t.Fatalf("loadGitChart() error = %v", err)
+	}
+	if commit != second {
+		t.Errorf("loadGitChart() commit = %s, want %s", commit, second)
+	}
+	if !hasCommitChanged(&operatorv1alpha1.HelmComponentStatus{Commit: first}, commit) {
+		t.Errorf("hasCommitChanged() = false after the branch moved")
+	}
+}
+
+func TestHelmAppReconciler_newGitAuth(t *testing.T) {
+	r := newFakeReconciler(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "basic", Namespace: "istio-system"},
+			Data: map[string][]byte{
+				repoSecretUsernameKey: []byte("user"),
+				repoSecretPasswordKey: []byte("pass"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "token", Namespace: "istio-system"},
+			Data:       map[string][]byte{repoSecretTokenKey: []byte("token")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "ssh-no-known-hosts", Namespace: "istio-system"},
+			Data:       map[string][]byte{gitSecretIdentityKey: []byte("invalid")},
+		},
+	)
+
+	tests := []struct {
+		name         string
+		secretName   string
+		expectedAuth string
+		expectErr    bool
+	}{
+		{
+			name:         "no secret",
+			expectedAuth: "",
+		},
+		{
+			name:         "basic auth",
+			secretName:   "basic",
+			expectedAuth: "http-basic-auth - user:*******",
+		},
+		{
+			name:         "token",
+			secretName:   "token",
+			expectedAuth: "http-basic-auth - git:*******",
+		},
+		{
+			name:       "invalid identity",
+			secretName: "ssh-no-known-hosts",
+			expectErr:  true,
+		},
+		{
+			name:       "missing secret",
+			secretName: "not-found",
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &operatorv1alpha1.GitChartSource{Url: "https://git.example.com/charts.git"}
+			if tt.secretName != "" {
+				source.SecretRef = &operatorv1alpha1.SecretReference{Name: tt.secretName}
+			}
+			auth, cleanup, err := r.newGitAuth(context.Background(), "istio-system", source)
+			defer cleanup()
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("newGitAuth() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newGitAuth() error = %v", err)
+			}
+			got := ""
+			if auth != nil {
+				got = auth.String()
+			}
+			if got != tt.expectedAuth {
+				t.Errorf("newGitAuth() = %q, want %q", got, tt.expectedAuth)
+			}
+		})
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"reflect"
 	"strconv"
 	"time"
 
@@ -15,19 +14,21 @@ import (
 
 	errors2 "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"pluma.io/pluma-operator/config"
+	"pluma.io/pluma-operator/internal/pkg/redact"
 	"pluma.io/pluma-operator/internal/pkg/schema"
 	"pluma.io/pluma-operator/internal/pkg/tools"
 
 	"github.com/hashicorp/go-multierror"
 	helmaction "helm.sh/helm/v3/pkg/action"
-	"helm.sh/helm/v3/pkg/chart/loader"
 	helmcli "helm.sh/helm/v3/pkg/cli"
 	"helm.sh/helm/v3/pkg/registry"
 	helmrelease "helm.sh/helm/v3/pkg/release"
 	"helm.sh/helm/v3/pkg/storage/driver"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/resource"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/record"
 	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
 	"pluma.io/pluma-operator/internal/pkg/constants"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -49,19 +50,23 @@ func debug(format string, v ...interface{}) {
 	}
 }
 
-func warning(format string, v ...interface{}) {
-	format = fmt.Sprintf("WARNING: %s\n", format)
-	fmt.Fprintf(os.Stderr, format, v...)
+// warning logs with the logger of the context, which masks the sensitive values of the reconciled HelmApp
+func warning(ctx context.Context, format string, v ...interface{}) {
+	ctllog.FromContext(ctx).Info(fmt.Sprintf("WARNING: "+format, v...))
 }
 
 // HelmAppReconciler reconciles a HelmApp object
 type HelmAppReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Config   config.Config
+	Recorder record.EventRecorder
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *HelmAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	settings.Debug = r.Config.Debug
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&operatorv1alpha1.HelmApp{}).
 		Complete(r)
@@ -96,16 +101,16 @@ func (r *HelmAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
-	// Initialize Helm client
-	helmCfg, err := newActionConfiguration()
-	if err != nil {
-		return ctrl.Result{RequeueAfter: serverFailedAfter}, fmt.Errorf("failed to new Helm action config: %v", err)
-	}
+	// Mask the sensitive values in everything logged for this HelmApp
+	redactor := newRedactor(helmApp)
+	ctx = ctllog.IntoContext(ctx, redactor.Logger(cLog))
+	cLog = ctllog.FromContext(ctx)
 
-	// Get the local kubeconfig
-	restClientGetter := genericclioptions.NewConfigFlags(true)
-	if err := helmCfg.Init(restClientGetter, helmApp.Namespace, "", debug); err != nil {
-		return ctrl.Result{RequeueAfter: serverFailedAfter}, fmt.Errorf("failed to initialize Helm action config: %w", err)
+	// Initialize Helm clients, one per namespace of the releases
+	helmCfgs := newHelmConfigs(redactor.Logf(debug))
+	helmCfg, err := helmCfgs.get(helmApp.Namespace)
+	if err != nil {
+		return ctrl.Result{RequeueAfter: serverFailedAfter}, err
 	}
 
 	// Create a map of desired components
@@ -114,13 +119,51 @@ func (r *HelmAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		desiredComponents[component.Name] = component
 	}
 
-	// Process each component
+	// Build the context of the values templates
+	renderer := r.newValuesRenderer(ctx, helmApp, func() (discovery.ServerVersionInterface, error) {
+		return helmCfg.RESTClientGetter.ToDiscoveryClient()
+	}, helmCfg.Releases)
+
+	// Uninstall the releases of the components moved to another namespace before installing them again,
+	// a failed uninstall is retried before the component is installed in its new namespace
 	var componentStatuses []*operatorv1alpha1.HelmComponentStatus
+	notMoved := map[string]bool{}
+	for _, existingStatus := range helmApp.Status.GetComponents() {
+		desired, exists := desiredComponents[existingStatus.Name]
+		if !exists || releaseNamespace(helmApp, desired) == statusNamespace(helmApp, existingStatus) {
+			continue
+		}
+		if err := r.uninstallStatusComponent(ctx, helmApp, existingStatus, helmCfgs); err != nil {
+			cLog.Error(err, fmt.Sprintf("Failed to uninstall component %s", existingStatus.Name))
+			notMoved[existingStatus.Name] = true
+			componentStatuses = append(componentStatuses, uninstallFailedStatus(existingStatus, err, redactor))
+			continue
+		}
+		cLog.Info("Uninstalled component from its previous namespace", "component", existingStatus.Name,
+			"namespace", statusNamespace(helmApp, existingStatus))
+	}
+
+	// Process each component
 	for _, component := range helmApp.Spec.Components {
-		status, err := r.reconcileComponent(ctx, helmApp, component, helmCfg)
+		if notMoved[component.Name] {
+			continue
+		}
+		var status *operatorv1alpha1.HelmComponentStatus
+		componentCfg, err := helmCfgs.get(releaseNamespace(helmApp, component))
+		if err != nil {
+			status = &operatorv1alpha1.HelmComponentStatus{
+				Name:      component.GetName(),
+				Namespace: releaseNamespace(helmApp, component),
+				Status:    helmrelease.StatusFailed.String(),
+				Message:   err.Error(),
+			}
+		} else {
+			status, err = r.reconcileComponent(ctx, helmApp, component, componentCfg, renderer, redactor)
+		}
 		if err != nil {
 			cLog.Error(err, fmt.Sprintf("Failed to reconcile component %s", component.Name))
 		}
+		status.Message = redactor.String(status.Message)
 		componentStatuses = append(componentStatuses, status)
 	}
 
@@ -128,19 +171,15 @@ func (r *HelmAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if helmApp.Status != nil {
 		for _, existingStatus := range helmApp.Status.Components {
 			if _, exists := desiredComponents[existingStatus.Name]; !exists && existingStatus.Name != "" {
-				if err := r.uninstallComponent(ctx, existingStatus.Name, helmCfg); err != nil {
+				if err := r.uninstallStatusComponent(ctx, helmApp, existingStatus, helmCfgs); err != nil {
 					cLog.Error(err, fmt.Sprintf("Failed to uninstall component %s", existingStatus.Name))
 					// Update component status with error message
-					componentStatuses = append(componentStatuses, &operatorv1alpha1.HelmComponentStatus{
-						Name:           existingStatus.Name,
-						Status:         helmrelease.StatusFailed.String(),
-						Message:        fmt.Sprintf("uninstall %s error: %v", existingStatus.Name, err),
-						Version:        existingStatus.Version,
-						Resources:      existingStatus.Resources,
-						ResourcesTotal: existingStatus.ResourcesTotal,
-					})
+					componentStatuses = append(componentStatuses, uninstallFailedStatus(existingStatus, err, redactor))
 				} else {
 					cLog.Info("Uninstalled component", "component", existingStatus.Name)
+					if err := r.deleteEffectiveValues(ctx, helmApp, existingStatus.Name); err != nil {
+						cLog.Error(err, "Failed to delete effective values", "component", existingStatus.Name)
+					}
 				}
 			}
 		}
@@ -200,19 +239,12 @@ func calculateOverallPhase(helmApp *operatorv1alpha1.HelmApp, componentStatuses
 }
 
 func (r *HelmAppReconciler) reconcileDelete(ctx context.Context, helmApp *operatorv1alpha1.HelmApp) (ctrl.Result, error) {
+	redactor := newRedactor(helmApp)
+	ctx = ctllog.IntoContext(ctx, redactor.Logger(ctllog.FromContext(ctx)))
 	cLog := ctllog.FromContext(ctx)
 
-	// Initialize Helm client
-	helmCfg, err := newActionConfiguration()
-	if err != nil {
-		return ctrl.Result{RequeueAfter: serverFailedAfter}, fmt.Errorf("failed to new Helm action config: %v", err)
-	}
-
-	// Get the local kubeconfig
-	restClientGetter := genericclioptions.NewConfigFlags(true)
-	if err := helmCfg.Init(restClientGetter, helmApp.Namespace, "", debug); err != nil {
-		return ctrl.Result{RequeueAfter: serverFailedAfter}, fmt.Errorf("failed to initialize Helm action config: %w", err)
-	}
+	// Initialize Helm clients, one per namespace of the releases
+	helmCfgs := newHelmConfigs(redactor.Logf(debug))
 
 	// Uninstall all components in reverse order
 	allComponentsUninstalled := true
@@ -222,7 +254,7 @@ func (r *HelmAppReconciler) reconcileDelete(ctx context.Context, helmApp *operat
 			cleanStatus := true
 			if component.Name != "" {
 				cLog.Info("Uninstalled component during deletion", "component", component.Name)
-				err := r.uninstallComponent(ctx, component.Name, helmCfg)
+				err := r.uninstallStatusComponent(ctx, helmApp, component, helmCfgs)
 				if err != nil {
 					cLog.Error(err, fmt.Sprintf("Failed to uninstall component %s during deletion", component.Name))
 					allComponentsUninstalled = false
@@ -230,7 +262,7 @@ func (r *HelmAppReconciler) reconcileDelete(ctx context.Context, helmApp *operat
 					err = fmt.Errorf("uninstall %s error: %v", component.Name, err)
 					// Update component status with error message
 					helmApp.Status.Components[i].Status = helmrelease.StatusFailed.String()
-					helmApp.Status.Components[i].Message = err.Error()
+					helmApp.Status.Components[i].Message = redactor.String(err.Error())
 					cleanStatus = false
 				}
 			}
@@ -262,78 +294,148 @@ func (r *HelmAppReconciler) reconcileDelete(ctx context.Context, helmApp *operat
 }
 
 func newActionConfiguration() (*helmaction.Configuration, error) {
+	// Create a new registry client
+	registryClient, err := newRegistryClient()
+	if err != nil {
+		return nil, err
+	}
+	return &helmaction.Configuration{
+		RegistryClient: registryClient,
+	}, nil
+}
+
+func newRegistryClient(extraOpts ...registry.ClientOption) (*registry.Client, error) {
 	opts := []registry.ClientOption{
 		registry.ClientOptDebug(settings.Debug),
 		registry.ClientOptEnableCache(true),
 		registry.ClientOptWriter(os.Stderr),
 	}
+	opts = append(opts, extraOpts...)
 
-	// Create a new registry client
 	registryClient, err := registry.NewClient(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("initializing new helm registry client: %s", err)
 	}
-	return &helmaction.Configuration{
-		RegistryClient: registryClient,
-	}, nil
+	return registryClient, nil
 }
 
 func newHelmSettings() *helmcli.EnvSettings {
-	helmSettings := helmcli.New()
-	helmSettings.Debug = true
+	return helmcli.New()
+}
 
-	return helmSettings
+// newRedactor returns the redactor of the sensitive values of the HelmApp spec
+func newRedactor(helmApp *operatorv1alpha1.HelmApp) *redact.Redactor {
+	redactor := redact.New(helmApp.Spec.GetRedactPaths())
+	redactor.AddValues(helmApp.Spec.GetGlobalValues().AsMap())
+	for _, component := range helmApp.Spec.GetComponents() {
+		redactor.AddValues(component.GetComponentValues().AsMap())
+	}
+	return redactor
 }
 
 func (r *HelmAppReconciler) reconcileComponent(ctx context.Context, helmApp *operatorv1alpha1.HelmApp, component *operatorv1alpha1.HelmComponent,
-	helmCfg *helmaction.Configuration) (componentStatus *operatorv1alpha1.HelmComponentStatus, err error) {
+	helmCfg *helmaction.Configuration, renderer *valuesRenderer, redactor *redact.Redactor) (componentStatus *operatorv1alpha1.HelmComponentStatus, err error) {
 	cLog := ctllog.FromContext(ctx)
 
-	// Merge global and component values
-	values := tools.MergeMaps(helmApp.Spec.GlobalValues.AsMap(), component.ComponentValues.AsMap())
-	if component.IgnoreGlobalValues {
-		values = component.ComponentValues.AsMap()
-	}
-
 	// Create component status
 	componentStatus = &operatorv1alpha1.HelmComponentStatus{
-		Name:    component.GetName(),
-		Status:  "unknown",
-		Version: "unknown",
+		Name:      component.GetName(),
+		Namespace: releaseNamespace(helmApp, component),
+		Status:    "unknown",
+		Version:   "unknown",
 	}
 
-	// Create a new install action
-	install := helmaction.NewInstall(helmCfg)
-	install.Namespace = helmApp.Namespace
-	install.ReleaseName = component.Name
-	install.Version = component.Version
-	install.RepoURL = helmApp.Spec.Repo.Url
-	install.ChartPathOptions.RepoURL = helmApp.Spec.Repo.Url
+	// Render the values templates
+	globalValues, err := renderer.render(helmApp.Spec.GlobalValues.AsMap())
+	if err != nil {
+		err = fmt.Errorf("failed to render global values: %w", err)
+		componentStatus.Status = helmrelease.StatusFailed.String()
+		componentStatus.Reason = reasonValuesTemplateFailed
+		componentStatus.Message = err.Error()
+		return
+	}
+	componentValues, err := renderer.render(component.ComponentValues.AsMap())
+	if err != nil {
+		err = fmt.Errorf("failed to render component values: %w", err)
+		componentStatus.Status = helmrelease.StatusFailed.String()
+		componentStatus.Reason = reasonValuesTemplateFailed
+		componentStatus.Message = err.Error()
+		return
+	}
 
-	// Locate the chart
-	cp, err := install.ChartPathOptions.LocateChart(component.Chart, settings)
+	// Merge global and component values
+	strategies, err := tools.ParseMergeStrategies(helmApp.Spec.GetMergeStrategies())
 	if err != nil {
-		err = fmt.Errorf("failed to locate chart: %w", err)
 		componentStatus.Message = err.Error()
 		return
 	}
+	values := tools.MergeMapsWithStrategies(globalValues, componentValues, strategies)
+	if component.IgnoreGlobalValues {
+		values = componentValues
+	}
+	// Values rendered from templates may hold sensitive values unknown from the spec
+	redactor.AddValues(values)
+
+	repo := getComponentRepo(helmApp, component)
 
-	// Load Chart
-	lChart, err := loader.Load(cp)
+	// Create a new install action
+	install := helmaction.NewInstall(helmCfg)
+	install.Namespace = releaseNamespace(helmApp, component)
+	install.ReleaseName = component.Name
+	install.Version = component.Version
+	install.RepoURL = repo.GetUrl()
+	install.ChartPathOptions.RepoURL = repo.GetUrl()
+
+	// Load the chart from its local source or from the repo
+	var (
+		lChart      *chart.Chart
+		chartDigest string
+		commit      string
+	)
+	if component.GetChartSource() != nil {
+		lChart, chartDigest, commit, err = r.loadSourceChart(ctx, helmApp.Namespace, component, repo)
+	} else {
+		lChart, chartDigest, err = r.loadRepoChart(ctx, helmApp.Namespace, component, repo, helmCfg.RegistryClient)
+	}
 	if err != nil {
-		err = fmt.Errorf("failed to load chart: %w", err)
+		var vErr *verificationError
+		if errors.As(err, &vErr) {
+			// Never install a chart that failed verification
+			componentStatus.Status = helmrelease.StatusFailed.String()
+			componentStatus.Reason = reasonVerificationFailed
+		}
 		componentStatus.Message = err.Error()
 		return
 	}
+	componentStatus.Digest = chartDigest
+	componentStatus.Commit = commit
 
 	// Apply schema-based filtering if enabled for this component
-	if component.EnableSchemaValidation {
+	schemaValidation := getSchemaValidation(component)
+	if shouldFilterValues(schemaValidation) {
 		cLog.Info("enable to filter values by schema", "component", component.Name)
-		filterValues, err := r.filterValuesBySchema(ctx, lChart, component, values)
+		filterValues, pruned, err := r.filterValuesBySchema(ctx, lChart, component, values)
 		if err != nil {
 			cLog.Error(err, "Failed to filter values by schema", "component", component.Name)
 		} else {
 			values = filterValues
+			componentStatus.PrunedPaths = pruned
+			r.recordPrunedValues(helmApp, component, pruned)
+		}
+	}
+	if component.ApplySchemaDefaults {
+		values, componentStatus.InjectedDefaults = r.applySchemaDefaults(ctx, lChart, component, values)
+	}
+	// Never install values violating the schema
+	if shouldValidateValues(schemaValidation) {
+		if err = r.validateValuesBySchema(ctx, lChart, component, values); err != nil {
+			var sErr *schemaValidationError
+			if errors.As(err, &sErr) {
+				componentStatus.Status = helmrelease.StatusFailed.String()
+				componentStatus.Reason = reasonSchemaValidationFailed
+			}
+			componentStatus.Message = err.Error()
+			return
 		}
 	}
 
@@ -341,6 +443,23 @@ func (r *HelmAppReconciler) reconcileComponent(ctx context.Context, helmApp *ope
 	var release *helmrelease.Release
 	mErrs := &multierror.Error{}
 
+	// applied is set once Helm holds the values, through an install, an upgrade or an up to date release
+	applied := false
+
+	// The hashes stored in the release labels detect the changes of the values, the chart and the overlays
+	hashes, err := newReleaseHashes(values, lChart, chartDigest, commit, component.Overlays)
+	if err != nil {
+		componentStatus.Message = err.Error()
+		return
+	}
+	install.Labels = hashes.labels()
+
+	// The overlays patch the rendered resources on install and upgrade
+	postRenderer := newOverlayPostRenderer(component)
+	if postRenderer != nil {
+		install.PostRenderer = postRenderer
+	}
+
 	histClient := helmaction.NewHistory(helmCfg)
 	histClient.Max = 1
 	history, err := histClient.Run(component.Name)
@@ -404,9 +523,9 @@ func (r *HelmAppReconciler) reconcileComponent(ctx context.Context, helmApp *ope
 							cAnno["meta.helm.sh/release-name"] = component.Name
 						}
 
-						if v, ok := cAnno["meta.helm.sh/release-namespace"]; !ok || v != component.Name {
+						if v, ok := cAnno["meta.helm.sh/release-namespace"]; !ok || v != releaseNamespace(helmApp, component) {
 							needUpdate = true
-							cAnno["meta.helm.sh/release-namespace"] = helmApp.Namespace
+							cAnno["meta.helm.sh/release-namespace"] = releaseNamespace(helmApp, component)
 						}
 						obj.SetAnnotations(cAnno)
 
@@ -426,27 +545,50 @@ func (r *HelmAppReconciler) reconcileComponent(ctx context.Context, helmApp *ope
 		}
 
 		// Release doesn't exist, install it
+		componentStatus.UpgradeReason = upgradeReasonInstall
 		release, err = install.Run(lChart, values)
 		if err != nil {
 			cLog.Error(err, "failed to install release")
 			multierror.Append(mErrs, fmt.Errorf("failed to install release: %v", err))
+		} else {
+			applied = true
 		}
 		cLog.Info("Installed release", "component", component.Name)
 	case err == nil:
 		// Release exists, check if update is needed
-		if len(history) > 0 && !hasConfigChanged(history[len(history)-1], values, component.Version) {
+		var last *helmrelease.Release
+		if len(history) > 0 {
+			last = history[len(history)-1]
+		}
+		reason, err := upgradeReason(last, getComponentStatus(helmApp, component.Name), component.Version, chartDigest, hashes)
+		if err != nil {
+			cLog.Error(err, "failed to compare release", "component", component.Name)
+			multierror.Append(mErrs, err)
+			release = last
+			break
+		}
+		componentStatus.UpgradeReason = reason
+		if reason == upgradeReasonUpToDate {
 			cLog.Info("No changes detected, skipping upgrade", "component", component.Name)
 			release = history[0]
+			applied = true
 		} else {
 			// Upgrade the release
+			cLog.Info("Upgrading release", "component", component.Name, "reason", reason)
 			upgrade := helmaction.NewUpgrade(helmCfg)
-			upgrade.Namespace = helmApp.Namespace
-			upgrade.RepoURL = helmApp.Spec.Repo.Url
+			upgrade.Namespace = releaseNamespace(helmApp, component)
+			upgrade.RepoURL = repo.GetUrl()
 			upgrade.Version = component.Version
+			upgrade.Labels = hashes.labels()
+			if postRenderer != nil {
+				upgrade.PostRenderer = postRenderer
+			}
 			release, err = upgrade.Run(component.Name, lChart, values)
 			if err != nil {
 				cLog.Error(err, "failed to upgrade release")
 				multierror.Append(mErrs, fmt.Errorf("failed to upgrade release: %v", err))
+			} else {
+				applied = true
 			}
 			cLog.Info("Upgraded release", "component", component.Name)
 		}
@@ -455,6 +597,26 @@ func (r *HelmAppReconciler) reconcileComponent(ctx context.Context, helmApp *ope
 		multierror.Append(mErrs, fmt.Errorf("helm releases history: %v", err))
 	}
 
+	// Only the values Helm holds are published, a failed install or upgrade keeps the last published ones
+	if applied {
+		layers := valuesLayers(values, globalValues, componentValues, component.IgnoreGlobalValues)
+		if err := r.publishEffectiveValues(ctx, helmApp, component.Name, componentStatus, values, layers, redactor); err != nil {
+			cLog.Error(err, "Failed to publish effective values", "component", component.Name)
+			multierror.Append(mErrs, err)
+		}
+	} else {
+		keepEffectiveValues(componentStatus, getComponentStatus(helmApp, component.Name))
+	}
+
+	// The overlays are only rendered on install and upgrade, an up to date release keeps the last unmatched ones
+	if postRenderer != nil {
+		if postRenderer.rendered {
+			componentStatus.UnmatchedOverlays = postRenderer.unmatched
+		} else {
+			componentStatus.UnmatchedOverlays = getComponentStatus(helmApp, component.Name).GetUnmatchedOverlays()
+		}
+	}
+
 	version := "unknown"
 	status := "unknown"
 	var resourcesStatus []*operatorv1alpha1.HelmResourceStatus
@@ -463,6 +625,9 @@ func (r *HelmAppReconciler) reconcileComponent(ctx context.Context, helmApp *ope
 	if release != nil {
 		version = strconv.Itoa(release.Version)
 		status = release.Info.Status.String()
+		if release.Chart != nil && release.Chart.Metadata != nil {
+			componentStatus.ChartVersion = release.Chart.Metadata.Version
+		}
 
 		// Parse the release manifest to get resource statuses
 		resources, err := resource.NewBuilder(helmCfg.RESTClientGetter).
@@ -496,6 +661,29 @@ func (r *HelmAppReconciler) reconcileComponent(ctx context.Context, helmApp *ope
 	return componentStatus, mErrs.ErrorOrNil()
 }
 
+// uninstallFailedStatus is the status of a component whose release failed to be uninstalled
+func uninstallFailedStatus(existingStatus *operatorv1alpha1.HelmComponentStatus, err error, redactor *redact.Redactor) *operatorv1alpha1.HelmComponentStatus {
+	return &operatorv1alpha1.HelmComponentStatus{
+		Name:           existingStatus.Name,
+		Namespace:      existingStatus.Namespace,
+		Status:         helmrelease.StatusFailed.String(),
+		Message:        redactor.String(fmt.Sprintf("uninstall %s error: %v", existingStatus.Name, err)),
+		Version:        existingStatus.Version,
+		Resources:      existingStatus.Resources,
+		ResourcesTotal: existingStatus.ResourcesTotal,
+	}
+}
+
+// uninstallStatusComponent uninstalls the release of a component status from its namespace
+func (r *HelmAppReconciler) uninstallStatusComponent(ctx context.Context, helmApp *operatorv1alpha1.HelmApp,
+	status *operatorv1alpha1.HelmComponentStatus, helmCfgs *helmConfigs) error {
+	helmCfg, err := helmCfgs.get(statusNamespace(helmApp, status))
+	if err != nil {
+		return err
+	}
+	return r.uninstallComponent(ctx, status.Name, helmCfg)
+}
+
 func (r *HelmAppReconciler) uninstallComponent(ctx context.Context, componentName string, helmCfg *helmaction.Configuration) error {
 	cLog := ctllog.FromContext(ctx)
 
@@ -519,29 +707,51 @@ func (r *HelmAppReconciler) uninstallComponent(ctx context.Context, componentNam
 	return err
 }
 
-func hasConfigChanged(release *helmrelease.Release, newValues map[string]any, newVersion string) bool {
-	if release.Chart.Metadata.Version != newVersion {
-		return true
+// hasDigestChanged reports whether the pulled chart differs from the one recorded in the last status,
+// which is the case when a tag is moved to a new chart with the same version
+func hasDigestChanged(lastStatus *operatorv1alpha1.HelmComponentStatus, newDigest string) bool {
+	return lastStatus.GetDigest() != "" && lastStatus.GetDigest() != newDigest
+}
+
+// hasCommitChanged reports whether the Git chart source moved to another commit since the last status
+func hasCommitChanged(lastStatus *operatorv1alpha1.HelmComponentStatus, newCommit string) bool {
+	return lastStatus.GetCommit() != "" && lastStatus.GetCommit() != newCommit
+}
+
+// getComponentStatus returns the last status of the component, or nil if there is none
+func getComponentStatus(helmApp *operatorv1alpha1.HelmApp, name string) *operatorv1alpha1.HelmComponentStatus {
+	for _, status := range helmApp.Status.GetComponents() {
+		if status.GetName() == name {
+			return status
+		}
 	}
-	return !reflect.DeepEqual(release.Config, newValues)
+	return nil
 }
 
-// filterValuesBySchema filters values based on the chart's values.schema.json
-func (r *HelmAppReconciler) filterValuesBySchema(ctx context.Context, cp *chart.Chart, component *operatorv1alpha1.HelmComponent, values map[string]interface{}) (map[string]interface{}, error) {
+// filterValuesBySchema filters values based on the chart's values.schema.json, or the schema inferred from the
+// default values when enabled. It returns the paths of the dropped values.
+func (r *HelmAppReconciler) filterValuesBySchema(ctx context.Context, cp *chart.Chart, component *operatorv1alpha1.HelmComponent,
+	values map[string]interface{}) (map[string]interface{}, []string, error) {
 	cLog := ctllog.FromContext(ctx)
 
 	// Load schema from chart
 	chartSchema, err := schema.LoadSchemaFromChart(cp)
 	if err != nil {
-		cLog.Info("No schema found in chart, skipping filtering", "component", component.Name, "error", err)
-		return values, nil // Return original values if no schema
+		inference := component.GetSchemaInference()
+		if !inference.GetEnabled() {
+			cLog.Info("No schema found in chart, skipping filtering", "component", component.Name, "error", err)
+			return values, nil, nil // Return original values if no schema
+		}
+		cLog.Info("No schema found in chart, inferring it from the default values", "component", component.Name, "error", err)
+		chartSchema = schema.InferSchemaFromChart(cp, inference.GetAllowedPaths())
 	}
 
 	// Create filter and filter values
 	filter := schema.NewFilter(chartSchema)
-	filteredValues := filter.FilterValues(values)
+	filteredValues, pruned := filter.FilterValues(values)
 
-	cLog.Info("Applied schema filtering", "component", component.Name, "originalKeys", len(values), "filteredKeys", len(filteredValues))
+	cLog.Info("Applied schema filtering", "component", component.Name, "originalKeys", len(values), "filteredKeys", len(filteredValues),
+		"pruned", pruned)
 
-	return filteredValues, nil
+	return filteredValues, pruned, nil
 }
@@ -1,10 +1,15 @@
 package controller
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/go-logr/logr/funcr"
+	"google.golang.org/protobuf/types/known/structpb"
 	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
 	"pluma.io/pluma-operator/internal/pkg/tools"
+	ctllog "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
 func TestHelmAppReconciler_SchemaValidation_Logic(t *testing.T) {
@@ -212,3 +217,26 @@ func TestHelmAppReconciler_ValueMerging(t *testing.T) {
 		})
 	}
 }
+
+func TestWarning_Redacted(t *testing.T) {
+	values, err := structpb.NewStruct(map[string]interface{}{"token": "my-token"})
+	if err != nil {
+		t.Fatalf("failed to build values: %v", err)
+	}
+	helmApp := &operatorv1alpha1.HelmApp{
+		Spec: &operatorv1alpha1.HelmAppSpec{GlobalValues: values},
+	}
+
+	var lines []string
+	l := newRedactor(helmApp).Logger(funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{}))
+	warning(ctllog.IntoContext(context.Background(), l), "failed to remove /tmp/my-token: %v", "my-token not found")
+
+	if len(lines) != 1 {
+		t.Fatalf("warning() logged %d lines, want 1", len(lines))
+	}
+	if strings.Contains(lines[0], "my-token") {
+		t.Errorf("warning() logged a sensitive value: %s", lines[0])
+	}
+}
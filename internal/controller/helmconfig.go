@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"fmt"
+
+	helmaction "helm.sh/helm/v3/pkg/action"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+// helmConfigs are the Helm action configurations of the namespaces the releases of a HelmApp are
+// installed in, initialized on first use
+type helmConfigs struct {
+	log     helmaction.DebugLog
+	configs map[string]*helmaction.Configuration
+}
+
+func newHelmConfigs(log helmaction.DebugLog) *helmConfigs {
+	return &helmConfigs{
+		log:     log,
+		configs: map[string]*helmaction.Configuration{},
+	}
+}
+
+// get returns the configuration of the releases of the namespace, it is also the default namespace
+// of the resources of their charts
+func (c *helmConfigs) get(namespace string) (*helmaction.Configuration, error) {
+	if cfg, ok := c.configs[namespace]; ok {
+		return cfg, nil
+	}
+
+	cfg, err := newActionConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("failed to new Helm action config: %v", err)
+	}
+	// Get the local kubeconfig
+	restClientGetter := genericclioptions.NewConfigFlags(true)
+	restClientGetter.Namespace = &namespace
+	if err := cfg.Init(restClientGetter, namespace, "", c.log); err != nil {
+		return nil, fmt.Errorf("failed to initialize Helm action config: %w", err)
+	}
+	c.configs[namespace] = cfg
+	return cfg, nil
+}
+
+// releaseNamespace returns the namespace the release of the component is installed in
+func releaseNamespace(helmApp *operatorv1alpha1.HelmApp, component *operatorv1alpha1.HelmComponent) string {
+	if ns := component.GetNamespace(); ns != "" {
+		return ns
+	}
+	return helmApp.Namespace
+}
+
+// statusNamespace returns the namespace of the release of a component status, the statuses written
+// before releases had their own namespace are the ones of the HelmApp namespace
+func statusNamespace(helmApp *operatorv1alpha1.HelmApp, status *operatorv1alpha1.HelmComponentStatus) string {
+	if ns := status.GetNamespace(); ns != "" {
+		return ns
+	}
+	return helmApp.Namespace
+}
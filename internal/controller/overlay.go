@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	yaml2 "gopkg.in/yaml.v2"
+	"istio.io/istio/operator/pkg/manifest"
+	"istio.io/istio/operator/pkg/tpath"
+	"istio.io/istio/operator/pkg/util"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+// overlayPostRenderer applies the overlays of a component to the resources rendered by its chart, with the path
+// syntax of the IstioOperator overlays. Helm only reports the errors of a post renderer, so the overlays and
+// patches matching nothing are kept in unmatched, e.g. `Deployment/istiod: spec.replicas.[0]`.
+type overlayPostRenderer struct {
+	overlays  []*operatorv1alpha1.HelmOverlay
+	rendered  bool
+	unmatched []string
+}
+
+// newOverlayPostRenderer returns nil when the component has no overlays
+func newOverlayPostRenderer(component *operatorv1alpha1.HelmComponent) *overlayPostRenderer {
+	if len(component.GetOverlays()) == 0 {
+		return nil
+	}
+	return &overlayPostRenderer{overlays: component.GetOverlays()}
+}
+
+// Run implements postrender.PostRenderer, the overlays match the resources by kind and name
+func (p *overlayPostRenderer) Run(renderedManifests *bytes.Buffer) (*bytes.Buffer, error) {
+	manifests, err := manifest.ParseMultiple(renderedManifests.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the rendered manifests: %w", err)
+	}
+
+	var unmatched []string
+	for _, overlay := range p.overlays {
+		matched := false
+		for i, m := range manifests {
+			if m.GetKind() != overlay.GetKind() || m.GetName() != overlay.GetName() {
+				continue
+			}
+			matched = true
+			patched, unmatchedPaths, err := applyOverlayPatches(m, overlay.GetPatches())
+			if err != nil {
+				return nil, fmt.Errorf("failed to apply the overlay of %s/%s: %w", overlay.GetKind(), overlay.GetName(), err)
+			}
+			manifests[i] = patched
+			for _, path := range unmatchedPaths {
+				unmatched = append(unmatched, fmt.Sprintf("%s/%s: %s", overlay.GetKind(), overlay.GetName(), path))
+			}
+		}
+		if !matched {
+			unmatched = append(unmatched, fmt.Sprintf("%s/%s", overlay.GetKind(), overlay.GetName()))
+		}
+	}
+	p.rendered = true
+	p.unmatched = unmatched
+
+	out := &bytes.Buffer{}
+	for _, m := range manifests {
+		out.WriteString("---\n")
+		out.WriteString(strings.TrimSpace(m.Content))
+		out.WriteString("\n")
+	}
+	return out, nil
+}
+
+// applyOverlayPatches applies the patches to a resource like IstioOperator does, a patch without value deletes
+// its path. It returns the paths of the patches matching nothing.
+func applyOverlayPatches(m manifest.Manifest, patches []*operatorv1alpha1.HelmOverlayPatch) (manifest.Manifest, []string, error) {
+	// yaml2 decodes the maps as map[any]any, which WritePathContext expects
+	obj := make(map[any]any)
+	if err := yaml2.Unmarshal([]byte(m.Content), &obj); err != nil {
+		return manifest.Manifest{}, nil, err
+	}
+	var unmatched []string
+	for _, patch := range patches {
+		pc, _, err := tpath.GetPathContext(obj, util.PathFromString(patch.GetPath()), true)
+		if err == nil {
+			err = tpath.WritePathContext(pc, patch.GetValue().AsInterface())
+		}
+		if err != nil {
+			unmatched = append(unmatched, patch.GetPath())
+		}
+	}
+	out, err := yaml2.Marshal(obj)
+	if err != nil {
+		return manifest.Manifest{}, nil, err
+	}
+	patched, err := manifest.FromYaml(out)
+	return patched, unmatched, err
+}
@@ -0,0 +1,178 @@
+package controller
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	yaml2 "gopkg.in/yaml.v2"
+	"istio.io/istio/operator/pkg/manifest"
+	"istio.io/istio/operator/pkg/tpath"
+	"istio.io/istio/operator/pkg/util"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+const overlayManifests = `---
+# Source: gateway/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: istio-ingressgateway
+  namespace: istio-system
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: istio-proxy
+        image: auto
+        args:
+        - proxy
+        - router
+---
+# Source: gateway/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: istio-ingressgateway
+  namespace: istio-system
+spec:
+  type: LoadBalancer
+  ports:
+  - name: http2
+    port: 80
+`
+
+func TestOverlayPostRenderer(t *testing.T) {
+	tests := []struct {
+		name              string
+		overlays          []*operatorv1alpha1.HelmOverlay
+		expected          map[string]string
+		expectedUnmatched []string
+	}{
+		{
+			name: "set a value",
+			overlays: []*operatorv1alpha1.HelmOverlay{{
+				Kind: "Deployment",
+				Name: "istio-ingressgateway",
+				Patches: []*operatorv1alpha1.HelmOverlayPatch{
+					{Path: "spec.replicas", Value: structpb.NewNumberValue(3)},
+				},
+			}},
+			expected: map[string]string{"Deployment:spec.replicas": "3"},
+		},
+		{
+			name: "set a value of a list element selected by name",
+			overlays: []*operatorv1alpha1.HelmOverlay{{
+				ApiVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "istio-ingressgateway",
+				Patches: []*operatorv1alpha1.HelmOverlayPatch{
+					{Path: "spec.template.spec.containers.[name:istio-proxy].image", Value: structpb.NewStringValue("proxyv2")},
+				},
+			}},
+			expected: map[string]string{"Deployment:spec.template.spec.containers.[name:istio-proxy].image": "proxyv2"},
+		},
+		{
+			name: "delete a value",
+			overlays: []*operatorv1alpha1.HelmOverlay{{
+				Kind: "Service",
+				Name: "istio-ingressgateway",
+				Patches: []*operatorv1alpha1.HelmOverlayPatch{
+					{Path: "spec.ports.[name:http2]"},
+				},
+			}},
+			expected: map[string]string{"Service:spec.ports": "[]"},
+		},
+		{
+			name: "unmatched resource and path",
+			overlays: []*operatorv1alpha1.HelmOverlay{
+				{
+					Kind: "Deployment",
+					Name: "istiod",
+					Patches: []*operatorv1alpha1.HelmOverlayPatch{
+						{Path: "spec.replicas", Value: structpb.NewNumberValue(3)},
+					},
+				},
+				{
+					Kind: "Deployment",
+					Name: "istio-ingressgateway",
+					Patches: []*operatorv1alpha1.HelmOverlayPatch{
+						{Path: "spec.template.spec.containers.[name:discovery].image", Value: structpb.NewStringValue("pilot")},
+						{Path: "spec.replicas", Value: structpb.NewNumberValue(2)},
+					},
+				},
+			},
+			expected: map[string]string{"Deployment:spec.replicas": "2"},
+			expectedUnmatched: []string{
+				"Deployment/istiod",
+				"Deployment/istio-ingressgateway: spec.template.spec.containers.[name:discovery].image",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			renderer := newOverlayPostRenderer(&operatorv1alpha1.HelmComponent{Overlays: tt.overlays})
+			out, err := renderer.Run(bytes.NewBufferString(overlayManifests))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			manifests, err := manifest.ParseMultiple(out.String())
+			if err != nil {
+				t.Fatalf("failed to parse the post rendered manifests: %v", err)
+			}
+			if len(manifests) != 2 {
+				t.Fatalf("Run() returned %d manifests, want 2", len(manifests))
+			}
+			for key, want := range tt.expected {
+				found := false
+				for _, m := range manifests {
+					kind, path, _ := strings.Cut(key, ":")
+					if m.GetKind() != kind {
+						continue
+					}
+					found = true
+					if got := manifestValue(t, m, path); got != want {
+						t.Errorf("Run() %s = %s, want %s", key, got, want)
+					}
+				}
+				if !found {
+					t.Errorf("Run() has no %s", key)
+				}
+			}
+			if !renderer.rendered {
+				t.Errorf("Run() did not record the rendering")
+			}
+			if !reflect.DeepEqual(renderer.unmatched, tt.expectedUnmatched) {
+				t.Errorf("Run() unmatched = %v, want %v", renderer.unmatched, tt.expectedUnmatched)
+			}
+		})
+	}
+}
+
+func TestNewOverlayPostRenderer_NoOverlays(t *testing.T) {
+	if renderer := newOverlayPostRenderer(&operatorv1alpha1.HelmComponent{}); renderer != nil {
+		t.Errorf("newOverlayPostRenderer() = %v, want nil", renderer)
+	}
+}
+
+// manifestValue returns the value at a path of the IstioOperator syntax as YAML
+func manifestValue(t *testing.T, m manifest.Manifest, path string) string {
+	t.Helper()
+	obj := make(map[any]any)
+	if err := yaml2.Unmarshal([]byte(m.Content), &obj); err != nil {
+		t.Fatalf("failed to unmarshal manifest: %v", err)
+	}
+	pc, found, err := tpath.GetPathContext(obj, util.PathFromString(path), false)
+	if err != nil || !found {
+		t.Fatalf("manifest has no %s: %v", path, err)
+	}
+	out, err := yaml2.Marshal(pc.Node)
+	if err != nil {
+		t.Fatalf("failed to marshal value: %v", err)
+	}
+	return string(bytes.TrimSpace(out))
+}
@@ -0,0 +1,531 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Keys read from the Secret referenced by HelmRepo.secretRef
+const (
+	repoSecretUsernameKey = "username"
+	repoSecretPasswordKey = "password"
+	repoSecretTokenKey    = "token"
+	repoSecretCertKey     = "tls.crt"
+	repoSecretKeyKey      = "tls.key"
+	repoSecretCAKey       = "ca.crt"
+)
+
+// repoAuth holds the credentials and TLS settings used to pull charts from a HelmRepo.
+// Certificates and registry credentials are written to a temporary directory,
+// which must be removed with cleanup once the chart has been located.
+type repoAuth struct {
+	url                   string
+	username              string
+	password              string
+	token                 string
+	certFile              string
+	keyFile               string
+	caFile                string
+	insecureSkipTLSVerify bool
+	passCredentialsAll    bool
+
+	dir string
+}
+
+// getComponentRepo returns the repo of the component, falling back to the HelmApp repo
+func getComponentRepo(helmApp *operatorv1alpha1.HelmApp, component *operatorv1alpha1.HelmComponent) *operatorv1alpha1.HelmRepo {
+	if component.GetRepo().GetUrl() != "" {
+		return component.GetRepo()
+	}
+	return helmApp.Spec.GetRepo()
+}
+
+// newRepoAuth loads the credentials of the repo from its secretRef
+func (r *HelmAppReconciler) newRepoAuth(ctx context.Context, namespace string, helmRepo *operatorv1alpha1.HelmRepo) (*repoAuth, error) {
+	auth := &repoAuth{
+		url:                   helmRepo.GetUrl(),
+		insecureSkipTLSVerify: helmRepo.GetInsecureSkipTLSVerify(),
+		passCredentialsAll:    helmRepo.GetPassCredentialsAll(),
+	}
+	if helmRepo.GetSecretRef().GetName() == "" {
+		return auth, nil
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: helmRepo.GetSecretRef().GetName()}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get repo secret %s: %w", key, err)
+	}
+
+	auth.username = string(secret.Data[repoSecretUsernameKey])
+	auth.password = string(secret.Data[repoSecretPasswordKey])
+	auth.token = string(secret.Data[repoSecretTokenKey])
+
+	files := map[string]*string{
+		repoSecretCertKey: &auth.certFile,
+		repoSecretKeyKey:  &auth.keyFile,
+		repoSecretCAKey:   &auth.caFile,
+	}
+	for k, f := range files {
+		data, ok := secret.Data[k]
+		if !ok || len(data) == 0 {
+			continue
+		}
+		p, err := auth.writeFile(k, data)
+		if err != nil {
+			auth.cleanup(ctx)
+			return nil, err
+		}
+		*f = p
+	}
+	if (auth.certFile == "") != (auth.keyFile == "") {
+		auth.cleanup(ctx)
+		return nil, fmt.Errorf("repo secret %s must contain both %s and %s", key, repoSecretCertKey, repoSecretKeyKey)
+	}
+
+	return auth, nil
+}
+
+// writeFile writes data into the temporary directory of the repoAuth
+func (a *repoAuth) writeFile(name string, data []byte) (string, error) {
+	if a.dir == "" {
+		dir, err := os.MkdirTemp("", "helm-repo-*")
+		if err != nil {
+			return "", fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		a.dir = dir
+	}
+	p := filepath.Join(a.dir, name)
+	if err := os.WriteFile(p, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return p, nil
+}
+
+// cleanup removes the temporary credential files
+func (a *repoAuth) cleanup(ctx context.Context) {
+	if a == nil || a.dir == "" {
+		return
+	}
+	if err := os.RemoveAll(a.dir); err != nil {
+		warning(ctx, "failed to remove repo credentials %s: %v", a.dir, err)
+	}
+	a.dir = ""
+}
+
+func (a *repoAuth) hasCredentials() bool {
+	return a.username != "" || a.password != "" || a.token != ""
+}
+
+func (a *repoAuth) hasTLSConfig() bool {
+	return a.certFile != "" || a.caFile != "" || a.insecureSkipTLSVerify
+}
+
+// tlsConfig builds the TLS config for the client certificate and CA of the repo
+func (a *repoAuth) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: a.insecureSkipTLSVerify, //nolint:gosec
+	}
+	if a.certFile != "" {
+		cert, err := tls.LoadX509KeyPair(a.certFile, a.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if a.caFile != "" {
+		data, err := os.ReadFile(a.caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("failed to parse CA bundle")
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+func (a *repoAuth) httpClient() (*http.Client, error) {
+	tlsCfg, err := a.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsCfg,
+		},
+	}, nil
+}
+
+// registryClient returns a registry client logged into the registry of ref.
+// The shared client is returned when the repo has no credentials or TLS settings.
+func (a *repoAuth) registryClient(ref string, shared *registry.Client) (*registry.Client, error) {
+	if !a.hasCredentials() && !a.hasTLSConfig() {
+		return shared, nil
+	}
+
+	var opts []registry.ClientOption
+	u, err := url.Parse(ref)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OCI reference %s: %w", ref, err)
+	}
+	if a.username != "" || a.password != "" {
+		data, err := json.Marshal(map[string]any{
+			"auths": map[string]any{u.Host: map[string]string{
+				"auth": base64.StdEncoding.EncodeToString([]byte(a.username + ":" + a.password)),
+			}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal registry credentials: %w", err)
+		}
+		credentialsFile, err := a.writeFile("config.json", data)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, registry.ClientOptCredentialsFile(credentialsFile))
+	}
+	if a.hasTLSConfig() || a.token != "" {
+		httpClient, err := a.httpClient()
+		if err != nil {
+			return nil, err
+		}
+		// helm passes the token of a credentials file as an OAuth2 refresh token to exchange,
+		// the bearer token is sent as is instead
+		if a.token != "" {
+			httpClient.Transport = &bearerTransport{base: httpClient.Transport, token: a.token, host: u.Host}
+		}
+		opts = append(opts, registry.ClientOptHTTPClient(httpClient))
+	}
+
+	return newRegistryClient(opts...)
+}
+
+// getters returns the getter providers for the repo, with bearer token support for HTTP repos
+func (a *repoAuth) getters() (getter.Providers, error) {
+	providers := getter.All(settings)
+	if a.token == "" {
+		return providers, nil
+	}
+
+	httpClient, err := a.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(a.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repo url %s: %w", a.url, err)
+	}
+	g := &bearerGetter{
+		client:             httpClient,
+		token:              a.token,
+		host:               u.Host,
+		passCredentialsAll: a.passCredentialsAll,
+	}
+	bearer := getter.Provider{
+		Schemes: []string{"http", "https"},
+		New: func(...getter.Option) (getter.Getter, error) {
+			return g, nil
+		},
+	}
+	// ByScheme returns the first provider, so the bearer getter takes precedence
+	return append(getter.Providers{bearer}, providers...), nil
+}
+
+// loadRepoChart pulls the chart of the component from the repo, verifies and loads it
+func (r *HelmAppReconciler) loadRepoChart(ctx context.Context, namespace string, component *operatorv1alpha1.HelmComponent,
+	helmRepo *operatorv1alpha1.HelmRepo, registryClient *registry.Client) (*chart.Chart, string, error) {
+	// Load the credentials of the chart repo
+	auth, err := r.newRepoAuth(ctx, namespace, helmRepo)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load repo credentials: %w", err)
+	}
+	defer auth.cleanup(ctx)
+
+	// Load the verification keys of the chart
+	verifier, err := r.newChartVerifier(ctx, namespace, getComponentVerification(component, helmRepo), auth)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load verification keys: %w", err)
+	}
+
+	// Locate the chart
+	cp, chartDigest, err := auth.locateChart(ctx, component.Chart, component.Version, component.Digest, verifier, registryClient)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to locate chart: %w", err)
+	}
+
+	// Load Chart
+	lChart, err := loader.Load(cp)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load chart: %w", err)
+	}
+	return lChart, chartDigest, nil
+}
+
+// locateChart downloads the chart from the repo and returns the path of the archive and its digest,
+// like ChartPathOptions.LocateChart but with the credentials of the repo.
+// Charts of OCI repos are referenced as <repo url>/<chart>.
+// The chart is verified when a verifier is given, failures are returned as *verificationError.
+func (a *repoAuth) locateChart(ctx context.Context, name, version, digest string, verifier *chartVerifier,
+	registryClient *registry.Client) (string, string, error) {
+	name = strings.TrimSpace(name)
+	version = strings.TrimSpace(version)
+	if digest != "" {
+		digest = normalizeDigest(strings.TrimSpace(digest))
+	}
+
+	if !registry.IsOCI(name) && registry.IsOCI(a.url) {
+		name = fmt.Sprintf("%s/%s", strings.TrimSuffix(a.url, "/"), name)
+	}
+
+	if err := os.MkdirAll(settings.RepositoryCache, 0o755); err != nil {
+		return "", "", err
+	}
+
+	if registry.IsOCI(name) {
+		rc, err := a.registryClient(name, registryClient)
+		if err != nil {
+			return "", "", err
+		}
+		filename, chartDigest, err := pullOCIChart(rc, name, version, digest, verifier.provenance())
+		if err != nil {
+			return "", "", err
+		}
+		if err := a.verifyChart(ctx, verifier, name, filename, chartDigest); err != nil {
+			return "", "", err
+		}
+		return filename, chartDigest, nil
+	}
+
+	getters, err := a.getters()
+	if err != nil {
+		return "", "", err
+	}
+
+	dl := downloader.ChartDownloader{
+		Out:     os.Stdout,
+		Getters: getters,
+		Options: []getter.Option{
+			getter.WithPassCredentialsAll(a.passCredentialsAll),
+			getter.WithTLSClientConfig(a.certFile, a.keyFile, a.caFile),
+			getter.WithInsecureSkipVerifyTLS(a.insecureSkipTLSVerify),
+		},
+		RepositoryConfig: settings.RepositoryConfig,
+		RepositoryCache:  settings.RepositoryCache,
+	}
+	if verifier.provenance() {
+		// Fetch the .prov file, it is verified below so that failures are reported as verification errors
+		dl.Verify = downloader.VerifyLater
+	}
+
+	if a.url != "" {
+		chartURL, err := repo.FindChartInAuthAndTLSAndPassRepoURL(a.url, a.username, a.password, name, version,
+			a.certFile, a.keyFile, a.caFile, a.insecureSkipTLSVerify, a.passCredentialsAll, getters)
+		if err != nil {
+			return "", "", err
+		}
+		name = chartURL
+
+		// Only pass the user/pass on when the user has said to or when the
+		// location of the chart repo and the chart are the same domain.
+		u1, err := url.Parse(a.url)
+		if err != nil {
+			return "", "", err
+		}
+		u2, err := url.Parse(chartURL)
+		if err != nil {
+			return "", "", err
+		}
+		if a.passCredentialsAll || (u1.Scheme == u2.Scheme && u1.Host == u2.Host) {
+			dl.Options = append(dl.Options, getter.WithBasicAuth(a.username, a.password))
+		}
+	} else {
+		dl.Options = append(dl.Options, getter.WithBasicAuth(a.username, a.password))
+	}
+
+	filename, _, err := dl.DownloadTo(name, version, settings.RepositoryCache)
+	if err != nil {
+		return "", "", err
+	}
+
+	sum, err := provenance.DigestFile(filename)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to digest chart: %w", err)
+	}
+	chartDigest := normalizeDigest(sum)
+	if digest != "" && digest != chartDigest {
+		return "", "", fmt.Errorf("chart digest %s does not match %s", chartDigest, digest)
+	}
+
+	p, err := filepath.Abs(filename)
+	if err != nil {
+		return "", "", err
+	}
+	if err := a.verifyChart(ctx, verifier, name, p, chartDigest); err != nil {
+		return "", "", err
+	}
+	return p, chartDigest, nil
+}
+
+// verifyChart verifies the pulled chart against the policy of the verifier, if any.
+// The archive is removed when the verification fails so that it can not be loaded by mistake.
+func (a *repoAuth) verifyChart(ctx context.Context, verifier *chartVerifier, name, filename, chartDigest string) error {
+	if verifier == nil {
+		return nil
+	}
+
+	var err error
+	switch verifier.mode {
+	case verificationModeProvenance:
+		err = verifier.verifyProvenance(filename)
+	case verificationModeCosign:
+		if !registry.IsOCI(name) {
+			err = &verificationError{err: fmt.Errorf("cosign verification requires an OCI chart, got %s", name)}
+		} else {
+			err = verifier.verifyCosign(ctx, a, name, chartDigest)
+		}
+	}
+	if err != nil {
+		_ = os.Remove(filename)
+	}
+	return err
+}
+
+// pullOCIChart pulls the chart from an OCI registry, by digest when it is pinned,
+// writes the archive to the repository cache and returns its path and the manifest digest.
+// The .prov file is written next to the archive when withProv is set and the chart has one.
+func pullOCIChart(rc *registry.Client, name, version, digest string, withProv bool) (string, string, error) {
+	ref := strings.TrimPrefix(name, fmt.Sprintf("%s://", registry.OCIScheme))
+
+	switch {
+	case digest != "":
+		ref = fmt.Sprintf("%s@%s", ref, digest)
+	case version != "" && isSemver(version):
+		ref = fmt.Sprintf("%s:%s", ref, version)
+	default:
+		// Resolve the highest tag matching the version constraint
+		tags, err := rc.Tags(ref)
+		if err != nil {
+			return "", "", err
+		}
+		tag, err := registry.GetTagMatchingVersionOrConstraint(tags, version)
+		if err != nil {
+			return "", "", err
+		}
+		ref = fmt.Sprintf("%s:%s", ref, tag)
+	}
+
+	result, err := rc.Pull(ref, registry.PullOptWithProv(withProv), registry.PullOptIgnoreMissingProv(true))
+	if err != nil {
+		return "", "", err
+	}
+	if digest != "" && result.Manifest.Digest != digest {
+		return "", "", fmt.Errorf("chart digest %s does not match %s", result.Manifest.Digest, digest)
+	}
+
+	meta := result.Chart.Meta
+	if digest != "" && version != "" {
+		// The digest wins over the tag, but it must still point to the requested version
+		if _, err := registry.GetTagMatchingVersionOrConstraint([]string{meta.Version}, version); err != nil {
+			return "", "", fmt.Errorf("chart %s@%s has version %s, want %s", name, digest, meta.Version, version)
+		}
+	}
+
+	filename := filepath.Join(settings.RepositoryCache, fmt.Sprintf("%s-%s.tgz", meta.Name, meta.Version))
+	if err := os.WriteFile(filename, result.Chart.Data, 0o644); err != nil {
+		return "", "", fmt.Errorf("failed to write chart: %w", err)
+	}
+	if result.Prov != nil && len(result.Prov.Data) > 0 {
+		if err := os.WriteFile(filename+".prov", result.Prov.Data, 0o644); err != nil {
+			return "", "", fmt.Errorf("failed to write provenance: %w", err)
+		}
+	}
+
+	p, err := filepath.Abs(filename)
+	return p, result.Manifest.Digest, err
+}
+
+// normalizeDigest prefixes bare sha256 sums, as found in index.yaml, with the algorithm
+func normalizeDigest(digest string) string {
+	if digest == "" || strings.Contains(digest, ":") {
+		return digest
+	}
+	return "sha256:" + digest
+}
+
+func isSemver(version string) bool {
+	_, err := semver.NewVersion(version)
+	return err == nil
+}
+
+// bearerTransport sends a static bearer token to the registry host, unless the request is already authorized
+type bearerTransport struct {
+	base  http.RoundTripper
+	token string
+	host  string
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == t.host && req.Header.Get("Authorization") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// bearerGetter fetches repository files with a bearer token,
+// which is not supported by the HTTP getter of helm
+type bearerGetter struct {
+	client             *http.Client
+	token              string
+	host               string
+	passCredentialsAll bool
+}
+
+func (g *bearerGetter) Get(href string, _ ...getter.Option) (*bytes.Buffer, error) {
+	req, err := http.NewRequest(http.MethodGet, href, nil)
+	if err != nil {
+		return nil, err
+	}
+	if g.passCredentialsAll || req.URL.Host == g.host {
+		req.Header.Set("Authorization", "Bearer "+g.token)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s : %s", href, resp.Status)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	_, err = io.Copy(buf, resp.Body)
+	return buf, err
+}
@@ -0,0 +1,461 @@
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/repo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeReconciler(objs ...runtime.Object) *HelmAppReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = operatorv1alpha1.AddToScheme(scheme)
+	return &HelmAppReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).Build(),
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func TestGetComponentRepo(t *testing.T) {
+	appRepo := &operatorv1alpha1.HelmRepo{Name: "app", Url: "https://charts.example.com"}
+	componentRepo := &operatorv1alpha1.HelmRepo{Name: "component", Url: "oci://harbor.example.com/charts"}
+
+	tests := []struct {
+		name      string
+		component *operatorv1alpha1.HelmComponent
+		expected  *operatorv1alpha1.HelmRepo
+	}{
+		{
+			name:      "component without repo uses the HelmApp repo",
+			component: &operatorv1alpha1.HelmComponent{Name: "base"},
+			expected:  appRepo,
+		},
+		{
+			name:      "component repo without url uses the HelmApp repo",
+			component: &operatorv1alpha1.HelmComponent{Name: "base", Repo: &operatorv1alpha1.HelmRepo{Name: "empty"}},
+			expected:  appRepo,
+		},
+		{
+			name:      "component repo overrides the HelmApp repo",
+			component: &operatorv1alpha1.HelmComponent{Name: "base", Repo: componentRepo},
+			expected:  componentRepo,
+		},
+	}
+
+	helmApp := &operatorv1alpha1.HelmApp{Spec: &operatorv1alpha1.HelmAppSpec{Repo: appRepo}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getComponentRepo(helmApp, tt.component); got != tt.expected {
+				t.Errorf("getComponentRepo() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHelmAppReconciler_newRepoAuth(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "harbor", Namespace: "istio-system"},
+		Data: map[string][]byte{
+			repoSecretUsernameKey: []byte("robot"),
+			repoSecretPasswordKey: []byte("s3cret"),
+			repoSecretCAKey:       []byte("ca-data"),
+		},
+	}
+	r := newFakeReconciler(secret)
+
+	helmRepo := &operatorv1alpha1.HelmRepo{
+		Url:                   "https://harbor.example.com/chartrepo/istio",
+		SecretRef:             &operatorv1alpha1.SecretReference{Name: "harbor"},
+		InsecureSkipTLSVerify: true,
+		PassCredentialsAll:    true,
+	}
+	auth, err := r.newRepoAuth(context.Background(), "istio-system", helmRepo)
+	if err != nil {
+		t.Fatalf("newRepoAuth() error = %v", err)
+	}
+	defer auth.cleanup(context.Background())
+
+	if auth.username != "robot" || auth.password != "s3cret" {
+		t.Errorf("credentials = %s/%s, want robot/s3cret", auth.username, auth.password)
+	}
+	if !auth.insecureSkipTLSVerify || !auth.passCredentialsAll {
+		t.Errorf("insecureSkipTLSVerify = %v, passCredentialsAll = %v, want both true", auth.insecureSkipTLSVerify, auth.passCredentialsAll)
+	}
+	if auth.certFile != "" || auth.keyFile != "" {
+		t.Errorf("unexpected client certificate files %s, %s", auth.certFile, auth.keyFile)
+	}
+	data, err := os.ReadFile(auth.caFile)
+	if err != nil {
+		t.Fatalf("failed to read CA file: %v", err)
+	}
+	if string(data) != "ca-data" {
+		t.Errorf("CA file = %s, want ca-data", data)
+	}
+
+	dir := auth.dir
+	auth.cleanup(context.Background())
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("temp dir %s should be removed after cleanup", dir)
+	}
+}
+
+func TestHelmAppReconciler_newRepoAuth_Errors(t *testing.T) {
+	r := newFakeReconciler(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "cert-only", Namespace: "istio-system"},
+		Data: map[string][]byte{
+			repoSecretCertKey: []byte("cert-data"),
+		},
+	})
+
+	tests := []struct {
+		name       string
+		secretName string
+	}{
+		{
+			name:       "missing secret",
+			secretName: "not-found",
+		},
+		{
+			name:       "client certificate without key",
+			secretName: "cert-only",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helmRepo := &operatorv1alpha1.HelmRepo{
+				Url:       "https://charts.example.com",
+				SecretRef: &operatorv1alpha1.SecretReference{Name: tt.secretName},
+			}
+			if _, err := r.newRepoAuth(context.Background(), "istio-system", helmRepo); err == nil {
+				t.Errorf("newRepoAuth() expected error")
+			}
+		})
+	}
+}
+
+func TestRepoAuth_registryClient(t *testing.T) {
+	tests := []struct {
+		name     string
+		auth     *repoAuth
+		expected map[string]string
+	}{
+		{
+			name: "basic auth",
+			auth: &repoAuth{username: "robot", password: "s3cret"},
+			expected: map[string]string{
+				"auth": base64.StdEncoding.EncodeToString([]byte("robot:s3cret")),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer tt.auth.cleanup(context.Background())
+
+			rc, err := tt.auth.registryClient("oci://harbor.example.com/istio/base", nil)
+			if err != nil {
+				t.Fatalf("registryClient() error = %v", err)
+			}
+			if rc == nil {
+				t.Fatalf("registryClient() returned nil client")
+			}
+
+			data, err := os.ReadFile(filepath.Join(tt.auth.dir, "config.json"))
+			if err != nil {
+				t.Fatalf("failed to read credentials file: %v", err)
+			}
+			var cfg struct {
+				Auths map[string]map[string]string `json:"auths"`
+			}
+			if err := json.Unmarshal(data, &cfg); err != nil {
+				t.Fatalf("failed to parse credentials file: %v", err)
+			}
+			got := cfg.Auths["harbor.example.com"]
+			for k, v := range tt.expected {
+				if got[k] != v {
+					t.Errorf("credentials[%s] = %s, want %s", k, got[k], v)
+				}
+			}
+		})
+	}
+
+	t.Run("anonymous repo uses the shared client", func(t *testing.T) {
+		auth := &repoAuth{}
+		rc, err := auth.registryClient("oci://harbor.example.com/istio/base", nil)
+		if err != nil {
+			t.Fatalf("registryClient() error = %v", err)
+		}
+		if rc != nil || auth.dir != "" {
+			t.Errorf("expected the shared client without temp files")
+		}
+	})
+}
+
+// testRegistryFile is a response of the test registry
+type testRegistryFile struct {
+	mediaType string
+	data      []byte
+}
+
+// newTestBearerRegistry serves the files by path to the requests authorized with the static bearer token,
+// the other requests are challenged with a token endpoint which rejects every token exchange
+func newTestBearerRegistry(t *testing.T, token string, files map[string]testRegistryFile) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			http.Error(w, "token exchange is not supported", http.StatusBadRequest)
+			return
+		}
+		if req.Header.Get("Authorization") != "Bearer "+token {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry"`, req.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		file, ok := files[req.URL.Path]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", file.mediaType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(file.data)))
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(file.data).String())
+		if req.Method != http.MethodHead {
+			_, _ = w.Write(file.data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRepoAuth_registryClient_BearerToken(t *testing.T) {
+	server := newTestBearerRegistry(t, "t0ken", map[string]testRegistryFile{
+		"/v2/istio/base/tags/list": {mediaType: "application/json", data: []byte(`{"name":"istio/base","tags":["1.24.2"]}`)},
+	})
+	host := server.Listener.Addr().String()
+
+	auth := &repoAuth{token: "t0ken", insecureSkipTLSVerify: true}
+	defer auth.cleanup(context.Background())
+	rc, err := auth.registryClient("oci://"+host+"/istio/base", nil)
+	if err != nil {
+		t.Fatalf("registryClient() error = %v", err)
+	}
+	tags, err := rc.Tags(host + "/istio/base")
+	if err != nil {
+		t.Fatalf("Tags() error = %v", err)
+	}
+	if len(tags) != 1 || tags[0] != "1.24.2" {
+		t.Errorf("Tags() = %v, want [1.24.2]", tags)
+	}
+}
+
+func TestBearerGetter_Get(t *testing.T) {
+	var gotAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		gotAuth = req.Header.Get("Authorization")
+		_, _ = w.Write([]byte("index"))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name               string
+		host               string
+		passCredentialsAll bool
+		expectedAuth       string
+	}{
+		{
+			name:         "same host sends the token",
+			host:         server.Listener.Addr().String(),
+			expectedAuth: "Bearer t0ken",
+		},
+		{
+			name:         "other host does not send the token",
+			host:         "charts.example.com",
+			expectedAuth: "",
+		},
+		{
+			name:               "pass credentials to all hosts",
+			host:               "charts.example.com",
+			passCredentialsAll: true,
+			expectedAuth:       "Bearer t0ken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAuth = ""
+			g := &bearerGetter{
+				client:             server.Client(),
+				token:              "t0ken",
+				host:               tt.host,
+				passCredentialsAll: tt.passCredentialsAll,
+			}
+			buf, err := g.Get(server.URL + "/index.yaml")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if buf.String() != "index" {
+				t.Errorf("Get() = %s, want index", buf.String())
+			}
+			if gotAuth != tt.expectedAuth {
+				t.Errorf("Authorization = %q, want %q", gotAuth, tt.expectedAuth)
+			}
+		})
+	}
+}
+
+// newTestChartRepo serves an HTTP chart repo with a single demo chart and returns its url and digest
+// newTestChartRepo serves a repo with the demo-0.1.0 chart, signed by the signer if given
+func newTestChartRepo(t *testing.T, signer *provenance.Signatory) (string, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	filename, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: "0.1.0"},
+	}, dir)
+	if err != nil {
+		t.Fatalf("failed to save chart: %v", err)
+	}
+	sum, err := provenance.DigestFile(filename)
+	if err != nil {
+		t.Fatalf("failed to digest chart: %v", err)
+	}
+	if signer != nil {
+		sig, err := signer.ClearSign(filename)
+		if err != nil {
+			t.Fatalf("failed to sign chart: %v", err)
+		}
+		if err := os.WriteFile(filename+".prov", []byte(sig), 0o644); err != nil {
+			t.Fatalf("failed to write provenance: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+
+	index := repo.NewIndexFile()
+	if err := index.MustAdd(&chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: "0.1.0"},
+		filepath.Base(filename), server.URL, sum); err != nil {
+		t.Fatalf("failed to add chart to index: %v", err)
+	}
+	if err := index.WriteFile(filepath.Join(dir, "index.yaml"), 0o644); err != nil {
+		t.Fatalf("failed to write index: %v", err)
+	}
+
+	return server.URL, sum
+}
+
+func TestRepoAuth_locateChart_Digest(t *testing.T) {
+	cache := settings.RepositoryCache
+	settings.RepositoryCache = t.TempDir()
+	defer func() { settings.RepositoryCache = cache }()
+
+	repoURL, sum := newTestChartRepo(t, nil)
+
+	tests := []struct {
+		name      string
+		digest    string
+		expectErr bool
+	}{
+		{
+			name: "no digest pinned",
+		},
+		{
+			name:   "bare sha256 from index.yaml",
+			digest: sum,
+		},
+		{
+			name:   "prefixed digest",
+			digest: "sha256:" + sum,
+		},
+		{
+			name:      "digest mismatch",
+			digest:    "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &repoAuth{url: repoURL}
+			p, digest, err := auth.locateChart(context.TODO(), "demo", "0.1.0", tt.digest, nil, nil)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("locateChart() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("locateChart() error = %v", err)
+			}
+			if filepath.Base(p) != "demo-0.1.0.tgz" {
+				t.Errorf("locateChart() path = %s, want demo-0.1.0.tgz", p)
+			}
+			if digest != "sha256:"+sum {
+				t.Errorf("locateChart() digest = %s, want sha256:%s", digest, sum)
+			}
+		})
+	}
+}
+
+func TestHasDigestChanged(t *testing.T) {
+	tests := []struct {
+		name       string
+		lastStatus *operatorv1alpha1.HelmComponentStatus
+		newDigest  string
+		expected   bool
+	}{
+		{
+			name:      "no previous status",
+			newDigest: "sha256:aaa",
+			expected:  false,
+		},
+		{
+			name:       "no previous digest",
+			lastStatus: &operatorv1alpha1.HelmComponentStatus{Name: "base"},
+			newDigest:  "sha256:aaa",
+			expected:   false,
+		},
+		{
+			name:       "same digest",
+			lastStatus: &operatorv1alpha1.HelmComponentStatus{Name: "base", Digest: "sha256:aaa"},
+			newDigest:  "sha256:aaa",
+			expected:   false,
+		},
+		{
+			name:       "digest changed with the same version",
+			lastStatus: &operatorv1alpha1.HelmComponentStatus{Name: "base", Digest: "sha256:aaa"},
+			newDigest:  "sha256:bbb",
+			expected:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hasDigestChanged(tt.lastStatus, tt.newDigest); got != tt.expected {
+				t.Errorf("hasDigestChanged() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/schema"
+	ctllog "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	schemaValidationFilter            = "Filter"
+	schemaValidationValidate          = "Validate"
+	schemaValidationFilterAndValidate = "FilterAndValidate"
+
+	reasonSchemaValidationFailed = "SchemaValidationFailed"
+	reasonValuesPruned           = "ValuesPruned"
+
+	// maxPrunedPathsInEvent bounds the size of the event message, the status lists every path
+	maxPrunedPathsInEvent = 10
+)
+
+// schemaValidationError lists the values violating the schema of the chart
+type schemaValidationError struct {
+	violations []schema.Violation
+}
+
+func (e *schemaValidationError) Error() string {
+	msgs := make([]string, 0, len(e.violations))
+	for _, v := range e.violations {
+		msgs = append(msgs, v.String())
+	}
+	return fmt.Sprintf("values do not match the chart schema: %s", strings.Join(msgs, "; "))
+}
+
+// getSchemaValidation returns the schema validation mode of the component, enableSchemaValidation is the Filter mode
+func getSchemaValidation(component *operatorv1alpha1.HelmComponent) string {
+	if mode := component.GetSchemaValidation(); mode != "" {
+		return mode
+	}
+	if component.GetEnableSchemaValidation() {
+		return schemaValidationFilter
+	}
+	return ""
+}
+
+func shouldFilterValues(mode string) bool {
+	return mode == schemaValidationFilter || mode == schemaValidationFilterAndValidate
+}
+
+func shouldValidateValues(mode string) bool {
+	return mode == schemaValidationValidate || mode == schemaValidationFilterAndValidate
+}
+
+// validateValuesBySchema checks the values merged with the chart defaults against the schema of the chart
+func (r *HelmAppReconciler) validateValuesBySchema(ctx context.Context, cp *chart.Chart, component *operatorv1alpha1.HelmComponent, values map[string]interface{}) error {
+	cLog := ctllog.FromContext(ctx)
+
+	chartSchema, err := schema.LoadSchemaFromChart(cp)
+	if err != nil {
+		cLog.Info("No schema found in chart, skipping validation", "component", component.Name, "error", err)
+		return nil
+	}
+
+	// Required properties may only be set by the chart defaults
+	coalesced, err := chartutil.CoalesceValues(cp, values)
+	if err != nil {
+		return fmt.Errorf("failed to merge values with the chart defaults: %w", err)
+	}
+
+	if violations := schema.Validate(chartSchema, coalesced); len(violations) > 0 {
+		return &schemaValidationError{violations: violations}
+	}
+	cLog.Info("Validated values against the chart schema", "component", component.Name)
+	return nil
+}
+
+// recordPrunedValues emits a Warning event listing the values dropped by the schema filtering
+func (r *HelmAppReconciler) recordPrunedValues(helmApp *operatorv1alpha1.HelmApp, component *operatorv1alpha1.HelmComponent, pruned []string) {
+	if len(pruned) == 0 {
+		return
+	}
+
+	paths := strings.Join(pruned, ", ")
+	if len(pruned) > maxPrunedPathsInEvent {
+		paths = fmt.Sprintf("%s and %d more", strings.Join(pruned[:maxPrunedPathsInEvent], ", "), len(pruned)-maxPrunedPathsInEvent)
+	}
+	r.Recorder.Eventf(helmApp, corev1.EventTypeWarning, reasonValuesPruned,
+		"Values of component %s not in the chart schema were dropped: %s", component.Name, paths)
+}
+
+// applySchemaDefaults fills the properties missing from the values with the defaults of the chart schema
+func (r *HelmAppReconciler) applySchemaDefaults(ctx context.Context, cp *chart.Chart, component *operatorv1alpha1.HelmComponent,
+	values map[string]interface{}) (map[string]interface{}, []string) {
+	cLog := ctllog.FromContext(ctx)
+
+	chartSchema, err := schema.LoadSchemaFromChart(cp)
+	if err != nil {
+		cLog.Info("No schema found in chart, skipping defaults", "component", component.Name, "error", err)
+		return values, nil
+	}
+
+	defaulted, injected := schema.NewFilter(chartSchema).ApplyDefaults(values, cp.Values)
+	cLog.Info("Applied schema defaults", "component", component.Name, "injected", len(injected))
+	return defaulted, injected
+}
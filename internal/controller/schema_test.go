@@ -0,0 +1,214 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"k8s.io/client-go/tools/record"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+func TestGetSchemaValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		component *operatorv1alpha1.HelmComponent
+		expected  string
+	}{
+		{name: "disabled", component: &operatorv1alpha1.HelmComponent{}, expected: ""},
+		{name: "legacy flag", component: &operatorv1alpha1.HelmComponent{EnableSchemaValidation: true}, expected: "Filter"},
+		{
+			name:      "mode wins over the legacy flag",
+			component: &operatorv1alpha1.HelmComponent{EnableSchemaValidation: true, SchemaValidation: "Validate"},
+			expected:  "Validate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getSchemaValidation(tt.component); got != tt.expected {
+				t.Errorf("getSchemaValidation() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHelmAppReconciler_validateValuesBySchema(t *testing.T) {
+	schemaChart := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "gateway", Version: "1.25.5"},
+		Values: map[string]interface{}{
+			"name":         "istio-ingressgateway",
+			"replicaCount": float64(1),
+		},
+		Raw: []*chart.File{{Name: "values.schema.json", Data: []byte(`{
+			"type": "object",
+			"required": ["name"],
+			"properties": {
+				"name": {"type": "string"},
+				"replicaCount": {"type": "integer"},
+				"service": {"type": "object", "properties": {"type": {"enum": ["ClusterIP", "LoadBalancer"]}}}
+			}
+		}`)}},
+	}
+	component := &operatorv1alpha1.HelmComponent{Name: "istio-ingressgateway", SchemaValidation: "Validate"}
+
+	tests := []struct {
+		name       string
+		chart      *chart.Chart
+		values     map[string]interface{}
+		violations []string
+	}{
+		{
+			name:   "required property set by the chart defaults",
+			chart:  schemaChart,
+			values: map[string]interface{}{"replicaCount": float64(2)},
+		},
+		{
+			name:  "violations",
+			chart: schemaChart,
+			values: map[string]interface{}{
+				"replicaCount": "two",
+				"service":      map[string]interface{}{"type": "NodePort"},
+			},
+			violations: []string{
+				"replicaCount: expected integer, got string",
+				"service.type: value NodePort is not one of [ClusterIP LoadBalancer]",
+			},
+		},
+		{
+			name:   "chart without schema",
+			chart:  &chart.Chart{Metadata: &chart.Metadata{Name: "base", Version: "1.25.5"}},
+			values: map[string]interface{}{"replicaCount": "two"},
+		},
+	}
+
+	r := newFakeReconciler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.validateValuesBySchema(context.Background(), tt.chart, component, tt.values)
+			if len(tt.violations) == 0 {
+				if err != nil {
+					t.Errorf("validateValuesBySchema() error = %v", err)
+				}
+				return
+			}
+			var sErr *schemaValidationError
+			if !errors.As(err, &sErr) {
+				t.Fatalf("validateValuesBySchema() error = %v, want a schema validation error", err)
+			}
+			for _, v := range tt.violations {
+				if !strings.Contains(err.Error(), v) {
+					t.Errorf("validateValuesBySchema() error = %v, want violation %q", err, v)
+				}
+			}
+		})
+	}
+}
+
+func TestHelmAppReconciler_recordPrunedValues(t *testing.T) {
+	helmApp := &operatorv1alpha1.HelmApp{}
+	component := &operatorv1alpha1.HelmComponent{Name: "istio-ingressgateway"}
+	manyPaths := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		manyPaths = append(manyPaths, fmt.Sprintf("labels.l%02d", i))
+	}
+
+	tests := []struct {
+		name     string
+		pruned   []string
+		expected string
+	}{
+		{name: "nothing pruned"},
+		{
+			name:     "pruned paths",
+			pruned:   []string{"autoscalling", "podAnnotations.foo"},
+			expected: "Warning ValuesPruned Values of component istio-ingressgateway not in the chart schema were dropped: autoscalling, podAnnotations.foo",
+		},
+		{
+			name:   "long lists are truncated",
+			pruned: manyPaths,
+			expected: "Warning ValuesPruned Values of component istio-ingressgateway not in the chart schema were dropped: " +
+				strings.Join(manyPaths[:10], ", ") + " and 2 more",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeReconciler()
+			r.recordPrunedValues(helmApp, component, tt.pruned)
+
+			recorder := r.Recorder.(*record.FakeRecorder)
+			select {
+			case event := <-recorder.Events:
+				if event != tt.expected {
+					t.Errorf("recordPrunedValues() event = %q, want %q", event, tt.expected)
+				}
+			default:
+				if tt.expected != "" {
+					t.Errorf("recordPrunedValues() recorded no event, want %q", tt.expected)
+				}
+			}
+		})
+	}
+}
+
+func TestHelmAppReconciler_filterValuesBySchema(t *testing.T) {
+	noSchemaChart := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "istiod", Version: "1.25.5"},
+		Values: map[string]interface{}{
+			"pilot":          map[string]interface{}{"replicaCount": float64(1)},
+			"podAnnotations": map[string]interface{}{},
+		},
+	}
+	values := map[string]interface{}{
+		"pilot":          map[string]interface{}{"replicaCount": float64(2), "replicaCnt": float64(3)},
+		"podAnnotations": map[string]interface{}{"foo": "bar"},
+		"autoscalling":   map[string]interface{}{"enabled": true},
+	}
+
+	tests := []struct {
+		name      string
+		component *operatorv1alpha1.HelmComponent
+		expected  map[string]interface{}
+		pruned    []string
+	}{
+		{
+			name:      "chart without schema",
+			component: &operatorv1alpha1.HelmComponent{Name: "istiod", SchemaValidation: "Filter"},
+			expected:  values,
+		},
+		{
+			name: "inferred schema",
+			component: &operatorv1alpha1.HelmComponent{
+				Name:             "istiod",
+				SchemaValidation: "Filter",
+				SchemaInference:  &operatorv1alpha1.SchemaInference{Enabled: true},
+			},
+			expected: map[string]interface{}{
+				"pilot":          map[string]interface{}{"replicaCount": float64(2)},
+				"podAnnotations": map[string]interface{}{"foo": "bar"},
+			},
+			pruned: []string{"autoscalling", "pilot.replicaCnt"},
+		},
+	}
+
+	r := newFakeReconciler()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, pruned, err := r.filterValuesBySchema(context.Background(), noSchemaChart, tt.component, values)
+			if err != nil {
+				t.Fatalf("filterValuesBySchema() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("filterValuesBySchema() = %v, want %v", got, tt.expected)
+			}
+			if !reflect.DeepEqual(pruned, tt.pruned) {
+				t.Errorf("filterValuesBySchema() pruned = %v, want %v", pruned, tt.pruned)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/provenance"
+	"helm.sh/helm/v3/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// chartSourceDefaultKey is the key of the packaged chart in a ConfigMap or Secret chart source
+const chartSourceDefaultKey = "chart.tgz"
+
+// loadSourceChart loads the chart of the component from its chart source and returns it with
+// the digest of the archive and the commit of Git sources. Charts loaded from a directory have no digest.
+// Chart sources have no provenance or signature to verify, a verification policy of the component or its
+// repo is rejected with a *verificationError rather than installing the chart unverified.
+func (r *HelmAppReconciler) loadSourceChart(ctx context.Context, namespace string,
+	component *operatorv1alpha1.HelmComponent, helmRepo *operatorv1alpha1.HelmRepo) (*chart.Chart, string, string, error) {
+	if policy := getComponentVerification(component, helmRepo); policy.GetMode() != "" {
+		return nil, "", "", &verificationError{err: fmt.Errorf("verification mode %s is not supported for chartSource", policy.GetMode())}
+	}
+	source := component.GetChartSource()
+
+	var (
+		lChart      *chart.Chart
+		chartDigest string
+		commit      string
+		err         error
+	)
+	switch {
+	case source.GetPath() != "":
+		lChart, chartDigest, err = loadPathChart(source.GetPath())
+	case source.GetConfigMapRef().GetName() != "":
+		lChart, chartDigest, err = r.loadConfigMapChart(ctx, namespace, source.GetConfigMapRef())
+	case source.GetSecretRef().GetName() != "":
+		lChart, chartDigest, err = r.loadSecretChart(ctx, namespace, source.GetSecretRef())
+	case source.GetGit().GetUrl() != "":
+		lChart, commit, err = r.loadGitChart(ctx, namespace, source.GetGit())
+	default:
+		err = fmt.Errorf("chartSource requires one of path, configMapRef, secretRef or git")
+	}
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to load chart source: %w", err)
+	}
+
+	if component.GetDigest() != "" {
+		digest := normalizeDigest(strings.TrimSpace(component.GetDigest()))
+		if chartDigest == "" {
+			return nil, "", "", fmt.Errorf("chart digest %s can only be checked for packaged charts", digest)
+		}
+		if digest != chartDigest {
+			return nil, "", "", fmt.Errorf("chart digest %s does not match %s", chartDigest, digest)
+		}
+	}
+	if err := checkChartVersion(lChart, component.GetVersion()); err != nil {
+		return nil, "", "", err
+	}
+
+	return lChart, chartDigest, commit, nil
+}
+
+// loadPathChart loads a chart directory or a packaged chart from the filesystem
+func loadPathChart(path string) (*chart.Chart, string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	lChart, err := loader.Load(path)
+	if err != nil {
+		return nil, "", err
+	}
+	if fi.IsDir() {
+		return lChart, "", nil
+	}
+
+	sum, err := provenance.DigestFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to digest chart: %w", err)
+	}
+	return lChart, normalizeDigest(sum), nil
+}
+
+func (r *HelmAppReconciler) loadConfigMapChart(ctx context.Context, namespace string,
+	ref *operatorv1alpha1.ChartArchiveReference) (*chart.Chart, string, error) {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: namespace, Name: ref.GetName()}
+	if err := r.Get(ctx, key, cm); err != nil {
+		return nil, "", fmt.Errorf("failed to get configmap %s: %w", key, err)
+	}
+
+	// kubectl stores files that are valid UTF-8 in data and the others in binaryData
+	data := make(map[string][]byte, len(cm.BinaryData)+len(cm.Data))
+	for k, v := range cm.Data {
+		data[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		data[k] = v
+	}
+
+	archive, err := readChartArchive(data, ref.GetKey())
+	if err != nil {
+		return nil, "", fmt.Errorf("configmap %s: %w", key, err)
+	}
+	return loadArchiveChart(archive)
+}
+
+func (r *HelmAppReconciler) loadSecretChart(ctx context.Context, namespace string,
+	ref *operatorv1alpha1.ChartArchiveReference) (*chart.Chart, string, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: ref.GetName()}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, "", fmt.Errorf("failed to get secret %s: %w", key, err)
+	}
+
+	archive, err := readChartArchive(secret.Data, ref.GetKey())
+	if err != nil {
+		return nil, "", fmt.Errorf("secret %s: %w", key, err)
+	}
+	return loadArchiveChart(archive)
+}
+
+// readChartArchive returns the packaged chart stored under key, or the concatenation
+// of its chunks <key>.0, <key>.1, ... when the archive was split.
+func readChartArchive(data map[string][]byte, key string) ([]byte, error) {
+	if key == "" {
+		key = chartSourceDefaultKey
+	}
+	if archive, ok := data[key]; ok {
+		return archive, nil
+	}
+
+	var chunks [][]byte
+	for i := 0; ; i++ {
+		chunk, ok := data[key+"."+strconv.Itoa(i)]
+		if !ok {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no chart archive found under key %s", key)
+	}
+	return bytes.Join(chunks, nil), nil
+}
+
+func loadArchiveChart(archive []byte) (*chart.Chart, string, error) {
+	lChart, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, "", err
+	}
+	return lChart, fmt.Sprintf("sha256:%x", sha256.Sum256(archive)), nil
+}
+
+// checkChartVersion checks the version of a local chart against the version or constraint of the component
+func checkChartVersion(lChart *chart.Chart, version string) error {
+	version = strings.TrimSpace(version)
+	if version == "" {
+		return nil
+	}
+	if _, err := registry.GetTagMatchingVersionOrConstraint([]string{lChart.Metadata.Version}, version); err != nil {
+		return fmt.Errorf("chart %s has version %s, want %s", lChart.Name(), lChart.Metadata.Version, version)
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+// newTestChartArchive packages the demo-0.1.0 chart and returns its path, content and digest
+func newTestChartArchive(t *testing.T) (string, []byte, string) {
+	t.Helper()
+
+	filename, err := chartutil.Save(&chart.Chart{
+		Metadata: &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "demo", Version: "0.1.0"},
+	}, t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to save chart: %v", err)
+	}
+	archive, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("failed to read chart: %v", err)
+	}
+	return filename, archive, fmt.Sprintf("sha256:%x", sha256.Sum256(archive))
+}
+
+func TestHelmAppReconciler_loadSourceChart(t *testing.T) {
+	filename, archive, digest := newTestChartArchive(t)
+	half := len(archive) / 2
+
+	r := newFakeReconciler(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo-chart", Namespace: "istio-system"},
+			BinaryData: map[string][]byte{chartSourceDefaultKey: archive},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo-chunked", Namespace: "istio-system"},
+			BinaryData: map[string][]byte{
+				"demo.tgz.0": archive[:half],
+				"demo.tgz.1": archive[half:],
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "demo-chart", Namespace: "istio-system"},
+			Data:       map[string][]byte{"demo.tgz": archive},
+		},
+	)
+
+	tests := []struct {
+		name            string
+		component       *operatorv1alpha1.HelmComponent
+		repo            *operatorv1alpha1.HelmRepo
+		expectedName    string
+		expectedVersion string
+		expectedDigest  string
+		expectErr       bool
+		expectVerifyErr bool
+	}{
+		{
+			name: "chart directory in the image",
+			component: &operatorv1alpha1.HelmComponent{
+				Version:     "1.22.8",
+				ChartSource: &operatorv1alpha1.ChartSource{Path: "../../istio/sample-charts/istio"},
+			},
+			expectedName:    "istio",
+			expectedVersion: "1.22.8",
+		},
+		{
+			name: "packaged chart on a volume",
+			component: &operatorv1alpha1.HelmComponent{
+				Version:     "0.1.0",
+				ChartSource: &operatorv1alpha1.ChartSource{Path: filename},
+			},
+			expectedName:    "demo",
+			expectedVersion: "0.1.0",
+			expectedDigest:  digest,
+		},
+		{
+			name: "configmap with default key",
+			component: &operatorv1alpha1.HelmComponent{
+				Digest:      digest,
+				ChartSource: &operatorv1alpha1.ChartSource{ConfigMapRef: &operatorv1alpha1.ChartArchiveReference{Name: "demo-chart"}},
+			},
+			expectedName:    "demo",
+			expectedVersion: "0.1.0",
+			expectedDigest:  digest,
+		},
+		{
+			name: "configmap with chunks",
+			component: &operatorv1alpha1.HelmComponent{
+				Version:     "~0.1",
+				ChartSource: &operatorv1alpha1.ChartSource{ConfigMapRef: &operatorv1alpha1.ChartArchiveReference{Name: "demo-chunked", Key: "demo.tgz"}},
+			},
+			expectedName:    "demo",
+			expectedVersion: "0.1.0",
+			expectedDigest:  digest,
+		},
+		{
+			name: "secret",
+			component: &operatorv1alpha1.HelmComponent{
+				ChartSource: &operatorv1alpha1.ChartSource{SecretRef: &operatorv1alpha1.ChartArchiveReference{Name: "demo-chart", Key: "demo.tgz"}},
+			},
+			expectedName:    "demo",
+			expectedVersion: "0.1.0",
+			expectedDigest:  digest,
+		},
+		{
+			name: "version mismatch",
+			component: &operatorv1alpha1.HelmComponent{
+				Version:     "0.2.0",
+				ChartSource: &operatorv1alpha1.ChartSource{ConfigMapRef: &operatorv1alpha1.ChartArchiveReference{Name: "demo-chart"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "digest mismatch",
+			component: &operatorv1alpha1.HelmComponent{
+				Digest:      "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+				ChartSource: &operatorv1alpha1.ChartSource{SecretRef: &operatorv1alpha1.ChartArchiveReference{Name: "demo-chart", Key: "demo.tgz"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "digest of a chart directory",
+			component: &operatorv1alpha1.HelmComponent{
+				Digest:      digest,
+				ChartSource: &operatorv1alpha1.ChartSource{Path: "../../istio/sample-charts/istio"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "missing key",
+			component: &operatorv1alpha1.HelmComponent{
+				ChartSource: &operatorv1alpha1.ChartSource{SecretRef: &operatorv1alpha1.ChartArchiveReference{Name: "demo-chart"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "missing configmap",
+			component: &operatorv1alpha1.HelmComponent{
+				ChartSource: &operatorv1alpha1.ChartSource{ConfigMapRef: &operatorv1alpha1.ChartArchiveReference{Name: "not-found"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "missing path",
+			component: &operatorv1alpha1.HelmComponent{
+				ChartSource: &operatorv1alpha1.ChartSource{Path: "/not/found"},
+			},
+			expectErr: true,
+		},
+		{
+			name: "verification of the component",
+			component: &operatorv1alpha1.HelmComponent{
+				Verification: &operatorv1alpha1.ChartVerification{Mode: verificationModeProvenance},
+				ChartSource:  &operatorv1alpha1.ChartSource{SecretRef: &operatorv1alpha1.ChartArchiveReference{Name: "demo-chart", Key: "demo.tgz"}},
+			},
+			expectVerifyErr: true,
+		},
+		{
+			name: "verification of the repo",
+			component: &operatorv1alpha1.HelmComponent{
+				ChartSource: &operatorv1alpha1.ChartSource{Path: "../../istio/sample-charts/istio"},
+			},
+			repo:            &operatorv1alpha1.HelmRepo{Verification: &operatorv1alpha1.ChartVerification{Mode: verificationModeCosign}},
+			expectVerifyErr: true,
+		},
+		{
+			name: "empty source",
+			component: &operatorv1alpha1.HelmComponent{
+				ChartSource: &operatorv1alpha1.ChartSource{},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lChart, chartDigest, _, err := r.loadSourceChart(context.Background(), "istio-system", tt.component, tt.repo)
+			if tt.expectVerifyErr {
+				var vErr *verificationError
+				if !errors.As(err, &vErr) {
+					t.Errorf("loadSourceChart() error = %v, want verification error", err)
+				}
+				return
+			}
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("loadSourceChart() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadSourceChart() error = %v", err)
+			}
+			if lChart.Name() != tt.expectedName || lChart.Metadata.Version != tt.expectedVersion {
+				t.Errorf("loadSourceChart() chart = %s-%s, want %s-%s", lChart.Name(), lChart.Metadata.Version,
+					tt.expectedName, tt.expectedVersion)
+			}
+			if chartDigest != tt.expectedDigest {
+				t.Errorf("loadSourceChart() digest = %s, want %s", chartDigest, tt.expectedDigest)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"pluma.io/pluma-operator/internal/pkg/tools"
+)
+
+// Reasons of the install or upgrade decision, reported in the component status
+const (
+	upgradeReasonInstall             = "Install"
+	upgradeReasonChartVersionChanged = "ChartVersionChanged"
+	upgradeReasonChartChanged        = "ChartChanged"
+	upgradeReasonValuesChanged       = "ValuesChanged"
+	upgradeReasonOverlaysChanged     = "OverlaysChanged"
+	upgradeReasonUpToDate            = "UpToDate"
+)
+
+// releaseHashLength is the length of the hashes stored in the release labels, a label value has at most 63 characters
+const releaseHashLength = 40
+
+// releaseHashes identify the values, the chart and the overlays of a release, overlays is empty without overlays.
+// The Git commit of a chart source is kept as is, a new commit upgrades the release even if the chart is unchanged.
+type releaseHashes struct {
+	values   string
+	chart    string
+	overlays string
+	commit   string
+}
+
+// newReleaseHashes hashes the canonical values, the chart and the overlays, the digest of the pulled archive
+// identifies the chart when known, otherwise its content
+func newReleaseHashes(values map[string]interface{}, cp *chart.Chart, chartDigest, commit string,
+	overlays []*operatorv1alpha1.HelmOverlay) (releaseHashes, error) {
+	valuesHash, err := tools.HashValues(values)
+	if err != nil {
+		return releaseHashes{}, fmt.Errorf("failed to hash values: %w", err)
+	}
+	overlaysHash, err := hashOverlays(overlays)
+	if err != nil {
+		return releaseHashes{}, fmt.Errorf("failed to hash overlays: %w", err)
+	}
+	chartHash := chartDigest
+	if chartHash == "" {
+		if chartHash, err = tools.HashChart(cp); err != nil {
+			return releaseHashes{}, fmt.Errorf("failed to hash chart: %w", err)
+		}
+	}
+	return releaseHashes{
+		values:   shortHash(valuesHash),
+		chart:    shortHash(chartHash),
+		overlays: shortHash(overlaysHash),
+		commit:   commit,
+	}, nil
+}
+
+// hashOverlays hashes the overlays as canonical values, it returns an empty hash without overlays
+func hashOverlays(overlays []*operatorv1alpha1.HelmOverlay) (string, error) {
+	if len(overlays) == 0 {
+		return "", nil
+	}
+	return tools.HashValues(map[string]interface{}{"overlays": overlays})
+}
+
+// labels returns the release labels holding the hashes
+func (h releaseHashes) labels() map[string]string {
+	labels := map[string]string{
+		constants.ReleaseValuesHashLabel: h.values,
+		constants.ReleaseChartHashLabel:  h.chart,
+	}
+	if h.overlays != "" {
+		labels[constants.ReleaseOverlaysHashLabel] = h.overlays
+	}
+	if h.commit != "" {
+		labels[constants.ReleaseCommitLabel] = h.commit
+	}
+	return labels
+}
+
+// upgradeReason returns why the last release must be upgraded, upgradeReasonUpToDate if it must not.
+// Releases installed before the hashes were stored compare the hash of their values, and the digest
+// and commit of the last status for the chart.
+func upgradeReason(last *helmrelease.Release, lastStatus *operatorv1alpha1.HelmComponentStatus, version, chartDigest string,
+	hashes releaseHashes) (string, error) {
+	if last == nil {
+		return upgradeReasonInstall, nil
+	}
+	if last.Chart != nil && last.Chart.Metadata != nil && last.Chart.Metadata.Version != version {
+		return upgradeReasonChartVersionChanged, nil
+	}
+
+	lastValues, ok := last.Labels[constants.ReleaseValuesHashLabel]
+	if !ok {
+		valuesHash, err := tools.HashValues(last.Config)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash the values of the release: %w", err)
+		}
+		lastValues = shortHash(valuesHash)
+	}
+	if lastValues != hashes.values {
+		return upgradeReasonValuesChanged, nil
+	}
+	if last.Labels[constants.ReleaseOverlaysHashLabel] != hashes.overlays {
+		return upgradeReasonOverlaysChanged, nil
+	}
+	if lastChart, ok := last.Labels[constants.ReleaseChartHashLabel]; ok {
+		if lastChart != hashes.chart {
+			return upgradeReasonChartChanged, nil
+		}
+	} else if hasDigestChanged(lastStatus, chartDigest) {
+		return upgradeReasonChartChanged, nil
+	}
+	// A new commit of a Git source upgrades the release even when the chart content is unchanged
+	if lastCommit, ok := last.Labels[constants.ReleaseCommitLabel]; ok {
+		if lastCommit != hashes.commit {
+			return upgradeReasonChartChanged, nil
+		}
+	} else if hasCommitChanged(lastStatus, hashes.commit) {
+		return upgradeReasonChartChanged, nil
+	}
+	return upgradeReasonUpToDate, nil
+}
+
+// shortHash returns the hex of a `sha256:<hex>` digest truncated to fit a label value
+func shortHash(digest string) string {
+	_, hex, found := strings.Cut(digest, ":")
+	if !found {
+		hex = digest
+	}
+	if len(hex) > releaseHashLength {
+		hex = hex[:releaseHashLength]
+	}
+	return hex
+}
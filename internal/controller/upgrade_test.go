@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"helm.sh/helm/v3/pkg/chart"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+)
+
+func TestUpgradeReason(t *testing.T) {
+	cp := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "gateway", Version: "1.25.5"},
+		Templates: []*chart.File{{Name: "templates/deployment.yaml", Data: []byte("kind: Deployment")}},
+	}
+	values := map[string]interface{}{"replicaCount": float64(2)}
+	hashes, err := newReleaseHashes(values, cp, "", "", nil)
+	if err != nil {
+		t.Fatalf("newReleaseHashes() error = %v", err)
+	}
+	overlays := []*operatorv1alpha1.HelmOverlay{{
+		Kind:    "Deployment",
+		Name:    "istio-ingressgateway",
+		Patches: []*operatorv1alpha1.HelmOverlayPatch{{Path: "spec.replicas", Value: structpb.NewNumberValue(3)}},
+	}}
+	overlaysHashes, err := newReleaseHashes(values, cp, "", "", overlays)
+	if err != nil {
+		t.Fatalf("newReleaseHashes() error = %v", err)
+	}
+	commitHashes, err := newReleaseHashes(values, cp, "", "1f3a5c7e9b1d3f5a7c9e1b3d5f7a9c1e3b5d7f9a", nil)
+	if err != nil {
+		t.Fatalf("newReleaseHashes() error = %v", err)
+	}
+	newCommitHashes, err := newReleaseHashes(values, cp, "", "9e7c5a3f1d9b7e5c3a1f9d7b5e3c1a9f7d5b3e1c", nil)
+	if err != nil {
+		t.Fatalf("newReleaseHashes() error = %v", err)
+	}
+	for _, h := range []releaseHashes{overlaysHashes, commitHashes} {
+		for _, hash := range h.labels() {
+			if len(hash) > 63 {
+				t.Errorf("newReleaseHashes() label value %s is longer than 63 characters", hash)
+			}
+		}
+	}
+
+	release := func(version string, labels map[string]string, config map[string]interface{}) *helmrelease.Release {
+		return &helmrelease.Release{
+			Chart:  &chart.Chart{Metadata: &chart.Metadata{Version: version}},
+			Config: config,
+			Labels: labels,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		last       *helmrelease.Release
+		lastStatus *operatorv1alpha1.HelmComponentStatus
+		digest     string
+		hashes     releaseHashes
+		expected   string
+	}{
+		{name: "no release", expected: upgradeReasonInstall},
+		{
+			name:     "up to date",
+			last:     release("1.25.5", hashes.labels(), nil),
+			expected: upgradeReasonUpToDate,
+		},
+		{
+			name:     "chart version changed",
+			last:     release("1.25.4", hashes.labels(), nil),
+			expected: upgradeReasonChartVersionChanged,
+		},
+		{
+			name: "values changed",
+			last: release("1.25.5", map[string]string{
+				constants.ReleaseValuesHashLabel: "0000",
+				constants.ReleaseChartHashLabel:  hashes.chart,
+			}, nil),
+			expected: upgradeReasonValuesChanged,
+		},
+		{
+			name: "chart changed under the same version",
+			last: release("1.25.5", map[string]string{
+				constants.ReleaseValuesHashLabel: hashes.values,
+				constants.ReleaseChartHashLabel:  "0000",
+			}, nil),
+			expected: upgradeReasonChartChanged,
+		},
+		{
+			name:     "overlays added",
+			last:     release("1.25.5", hashes.labels(), nil),
+			hashes:   overlaysHashes,
+			expected: upgradeReasonOverlaysChanged,
+		},
+		{
+			name:     "overlays removed",
+			last:     release("1.25.5", overlaysHashes.labels(), nil),
+			expected: upgradeReasonOverlaysChanged,
+		},
+		{
+			name:     "overlays up to date",
+			last:     release("1.25.5", overlaysHashes.labels(), nil),
+			hashes:   overlaysHashes,
+			expected: upgradeReasonUpToDate,
+		},
+		{
+			name:     "new commit with the same chart",
+			last:     release("1.25.5", commitHashes.labels(), nil),
+			hashes:   newCommitHashes,
+			expected: upgradeReasonChartChanged,
+		},
+		{
+			name:     "same commit",
+			last:     release("1.25.5", commitHashes.labels(), nil),
+			hashes:   commitHashes,
+			expected: upgradeReasonUpToDate,
+		},
+		{
+			name:       "release without commit label and a new commit",
+			last:       release("1.25.5", hashes.labels(), nil),
+			lastStatus: &operatorv1alpha1.HelmComponentStatus{Commit: commitHashes.commit},
+			hashes:     newCommitHashes,
+			expected:   upgradeReasonChartChanged,
+		},
+		{
+			name:     "release without hashes holding ints",
+			last:     release("1.25.5", nil, map[string]interface{}{"replicaCount": 2}),
+			expected: upgradeReasonUpToDate,
+		},
+		{
+			name:       "release without hashes and a new digest",
+			last:       release("1.25.5", nil, map[string]interface{}{"replicaCount": 2}),
+			lastStatus: &operatorv1alpha1.HelmComponentStatus{Digest: "sha256:old"},
+			digest:     "sha256:new",
+			expected:   upgradeReasonChartChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := hashes
+			if tt.hashes != (releaseHashes{}) {
+				current = tt.hashes
+			}
+			got, err := upgradeReason(tt.last, tt.lastStatus, "1.25.5", tt.digest, current)
+			if err != nil {
+				t.Fatalf("upgradeReason() error = %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("upgradeReason() = %s, want %s", got, tt.expected)
+			}
+		})
+	}
+}
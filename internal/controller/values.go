@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"helm.sh/helm/v3/pkg/storage"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/discovery"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/tools"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reasonValuesTemplateFailed is the status reason of components whose values templates failed to render
+const reasonValuesTemplateFailed = "ValuesTemplateFailed"
+
+// valuesTemplateContext is the data the values templates of a HelmApp are rendered with
+type valuesTemplateContext struct {
+	// Name and Namespace of the HelmApp
+	Name      string
+	Namespace string
+	// KubeVersion is the version of the cluster, e.g. v1.30.2
+	KubeVersion string
+	// ConfigMaps holds the data of the ConfigMaps listed in valuesTemplating.configMaps by name
+	ConfigMaps map[string]map[string]string
+	// Components holds the chart and the resolved version of each component of the HelmApp
+	Components map[string]valuesTemplateComponent
+}
+
+type valuesTemplateComponent struct {
+	Chart string
+	// Version is the chart version of the last release, or the version of the spec before the first install
+	Version string
+}
+
+// valuesRenderer renders the values templates of a HelmApp. A nil renderer leaves the values untouched.
+type valuesRenderer struct {
+	data *valuesTemplateContext
+	// err is the error met while building the context, it is reported by every render
+	err error
+}
+
+// discoveryFunc creates the discovery client the kubernetes version is read from
+type discoveryFunc func() (discovery.ServerVersionInterface, error)
+
+// newValuesRenderer builds the template context of the HelmApp, it returns nil if templating is not enabled.
+// The discovery client is only created when templating is enabled.
+func (r *HelmAppReconciler) newValuesRenderer(ctx context.Context, helmApp *operatorv1alpha1.HelmApp,
+	newDiscovery discoveryFunc, releases *storage.Storage) *valuesRenderer {
+	if !helmApp.Spec.GetValuesTemplating().GetEnabled() {
+		return nil
+	}
+
+	data := &valuesTemplateContext{
+		Name:       helmApp.Name,
+		Namespace:  helmApp.Namespace,
+		ConfigMaps: map[string]map[string]string{},
+		Components: map[string]valuesTemplateComponent{},
+	}
+
+	dc, err := newDiscovery()
+	if err != nil {
+		return &valuesRenderer{err: fmt.Errorf("failed to create discovery client: %w", err)}
+	}
+	version, err := dc.ServerVersion()
+	if err != nil {
+		return &valuesRenderer{err: fmt.Errorf("failed to get the kubernetes version: %w", err)}
+	}
+	data.KubeVersion = version.GitVersion
+
+	for _, name := range helmApp.Spec.GetValuesTemplating().GetConfigMaps() {
+		cm := &corev1.ConfigMap{}
+		key := client.ObjectKey{Namespace: helmApp.Namespace, Name: name}
+		if err := r.Get(ctx, key, cm); err != nil {
+			return &valuesRenderer{err: fmt.Errorf("failed to get configmap %s: %w", key, err)}
+		}
+		data.ConfigMaps[name] = cm.Data
+	}
+
+	for _, component := range helmApp.Spec.Components {
+		c := valuesTemplateComponent{Chart: component.GetChart(), Version: component.GetVersion()}
+		if rel, err := releases.Last(component.GetName()); err == nil && rel.Chart != nil && rel.Chart.Metadata != nil {
+			c.Version = rel.Chart.Metadata.Version
+		}
+		data.Components[component.GetName()] = c
+	}
+
+	return &valuesRenderer{data: data}
+}
+
+// render renders the templates of the values, the values are returned as is by a nil renderer
+func (v *valuesRenderer) render(values map[string]interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return values, nil
+	}
+	if v.err != nil {
+		return nil, v.err
+	}
+	return tools.RenderValues(values, v.data)
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"helm.sh/helm/v3/pkg/chart"
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+func TestHelmAppReconciler_newValuesRenderer(t *testing.T) {
+	r := newFakeReconciler(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-info", Namespace: "istio-system"},
+		Data:       map[string]string{"name": "prod-east"},
+	})
+	dc := &fakediscovery.FakeDiscovery{
+		Fake:               &clienttesting.Fake{},
+		FakedServerVersion: &version.Info{GitVersion: "v1.30.2"},
+	}
+	releases := storage.Init(driver.NewMemory())
+	if err := releases.Create(&helmrelease.Release{
+		Name:      "istio-base",
+		Namespace: "istio-system",
+		Version:   1,
+		Info:      &helmrelease.Info{Status: helmrelease.StatusDeployed},
+		Chart:     &chart.Chart{Metadata: &chart.Metadata{Name: "base", Version: "1.22.8"}},
+	}); err != nil {
+		t.Fatalf("failed to create release: %v", err)
+	}
+
+	untemplated := map[string]interface{}{"namespace": "{{ .Namespace }}"}
+
+	tests := []struct {
+		name       string
+		templating *operatorv1alpha1.ValuesTemplating
+		values     map[string]interface{}
+		expected   map[string]interface{}
+		// discoveryErr fails the creation of the discovery client
+		discoveryErr error
+		expectErr    bool
+	}{
+		{
+			name:     "templating disabled",
+			values:   untemplated,
+			expected: untemplated,
+		},
+		{
+			name:       "templating enabled",
+			templating: &operatorv1alpha1.ValuesTemplating{Enabled: true, ConfigMaps: []string{"cluster-info"}},
+			values: map[string]interface{}{
+				"global": map[string]interface{}{
+					"istioNamespace": "{{ .Namespace }}",
+					"clusterName":    `{{ index .ConfigMaps "cluster-info" "name" }}`,
+					"kubeVersion":    "{{ .KubeVersion }}",
+				},
+				"baseVersion":    `{{ (index .Components "istio-base").Version }}`,
+				"istiodVersion":  `{{ (index .Components "istio-istiod").Version }}`,
+				"appName":        "{{ .Name }}",
+				"untouchedValue": float64(1),
+			},
+			expected: map[string]interface{}{
+				"global": map[string]interface{}{
+					"istioNamespace": "istio-system",
+					"clusterName":    "prod-east",
+					"kubeVersion":    "v1.30.2",
+				},
+				// resolved from the last release
+				"baseVersion": "1.22.8",
+				// not installed yet, from the spec
+				"istiodVersion":  "1.23.0",
+				"appName":        "istio",
+				"untouchedValue": float64(1),
+			},
+		},
+		{
+			name:         "templating disabled without discovery client",
+			values:       untemplated,
+			expected:     untemplated,
+			discoveryErr: errors.New("no cluster"),
+		},
+		{
+			name:         "discovery client failure",
+			templating:   &operatorv1alpha1.ValuesTemplating{Enabled: true},
+			values:       map[string]interface{}{"name": "static"},
+			discoveryErr: errors.New("no cluster"),
+			expectErr:    true,
+		},
+		{
+			name:       "missing configmap",
+			templating: &operatorv1alpha1.ValuesTemplating{Enabled: true, ConfigMaps: []string{"not-found"}},
+			values:     map[string]interface{}{"name": "static"},
+			expectErr:  true,
+		},
+		{
+			name:       "unknown field",
+			templating: &operatorv1alpha1.ValuesTemplating{Enabled: true},
+			values:     map[string]interface{}{"name": "{{ .ClusterName }}"},
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helmApp := &operatorv1alpha1.HelmApp{
+				ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system"},
+				Spec: &operatorv1alpha1.HelmAppSpec{
+					Components: []*operatorv1alpha1.HelmComponent{
+						{Name: "istio-base", Chart: "base", Version: "1.23.0"},
+						{Name: "istio-istiod", Chart: "istiod", Version: "1.23.0"},
+					},
+					ValuesTemplating: tt.templating,
+				},
+			}
+
+			newDiscovery := func() (discovery.ServerVersionInterface, error) {
+				if tt.discoveryErr != nil {
+					return nil, tt.discoveryErr
+				}
+				return dc, nil
+			}
+			renderer := r.newValuesRenderer(context.Background(), helmApp, newDiscovery, releases)
+			got, err := renderer.render(tt.values)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("render() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("render() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("render() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHelmAppReconciler_reconcileComponent_TemplateFailed(t *testing.T) {
+	helmApp := &operatorv1alpha1.HelmApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system"},
+		Spec: &operatorv1alpha1.HelmAppSpec{
+			Components: []*operatorv1alpha1.HelmComponent{{Name: "istio-base", Chart: "base", Version: "1.23.0"}},
+		},
+	}
+	renderer := &valuesRenderer{data: &valuesTemplateContext{}}
+	component := &operatorv1alpha1.HelmComponent{
+		Name:  "istio-base",
+		Chart: "base",
+	}
+	values, err := structpb.NewStruct(map[string]interface{}{"name": "{{ .ClusterName }}"})
+	if err != nil {
+		t.Fatalf("failed to create values: %v", err)
+	}
+	component.ComponentValues = values
+
+	status, err := newFakeReconciler().reconcileComponent(context.Background(), helmApp, component, nil, renderer, nil)
+	if err == nil {
+		t.Fatalf("reconcileComponent() expected error")
+	}
+	if status.Status != helmrelease.StatusFailed.String() || status.Reason != reasonValuesTemplateFailed {
+		t.Errorf("reconcileComponent() status = %s, reason = %s, want %s, %s",
+			status.Status, status.Reason, helmrelease.StatusFailed, reasonValuesTemplateFailed)
+	}
+}
@@ -0,0 +1,289 @@
+package controller
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/downloader"
+	"helm.sh/helm/v3/pkg/registry"
+	corev1 "k8s.io/api/core/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	verificationModeProvenance = "Provenance"
+	verificationModeCosign     = "Cosign"
+
+	// Keys read from the Secret referenced by ChartVerification.secretRef
+	verificationSecretKeyringKey   = "keyring.gpg"
+	verificationSecretPubKeySuffix = ".pub"
+
+	// reasonVerificationFailed is the status reason of components whose chart failed verification
+	reasonVerificationFailed = "VerificationFailed"
+
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// verificationError is returned when a chart fails provenance or signature verification
+type verificationError struct {
+	err error
+}
+
+func (e *verificationError) Error() string {
+	return fmt.Sprintf("chart verification failed: %v", e.err)
+}
+
+func (e *verificationError) Unwrap() error {
+	return e.err
+}
+
+// getComponentVerification returns the verification policy of the component, falling back to the one of its repo
+func getComponentVerification(component *operatorv1alpha1.HelmComponent, helmRepo *operatorv1alpha1.HelmRepo) *operatorv1alpha1.ChartVerification {
+	if component.GetVerification().GetMode() != "" {
+		return component.GetVerification()
+	}
+	return helmRepo.GetVerification()
+}
+
+// chartVerifier verifies pulled charts against a ChartVerification policy
+type chartVerifier struct {
+	mode       string
+	keyring    string
+	publicKeys []crypto.PublicKey
+}
+
+// newChartVerifier loads the keys of the policy, it returns nil if the policy does not require verification.
+// The keyring is written to the temporary directory of the repoAuth.
+func (r *HelmAppReconciler) newChartVerifier(ctx context.Context, namespace string, policy *operatorv1alpha1.ChartVerification,
+	repoAuth *repoAuth) (*chartVerifier, error) {
+	if policy.GetMode() == "" {
+		return nil, nil
+	}
+	if policy.GetSecretRef().GetName() == "" {
+		return nil, fmt.Errorf("verification mode %s requires a secretRef", policy.GetMode())
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: policy.GetSecretRef().GetName()}
+	if err := r.Get(ctx, key, secret); err != nil {
+		return nil, fmt.Errorf("failed to get verification secret %s: %w", key, err)
+	}
+
+	v := &chartVerifier{mode: policy.GetMode()}
+	switch v.mode {
+	case verificationModeProvenance:
+		data, ok := secret.Data[verificationSecretKeyringKey]
+		if !ok || len(data) == 0 {
+			return nil, fmt.Errorf("verification secret %s has no %s", key, verificationSecretKeyringKey)
+		}
+		keyring, err := repoAuth.writeFile(verificationSecretKeyringKey, data)
+		if err != nil {
+			return nil, err
+		}
+		v.keyring = keyring
+	case verificationModeCosign:
+		// sort the keys to try them in a stable order
+		names := make([]string, 0, len(secret.Data))
+		for k := range secret.Data {
+			if strings.HasSuffix(k, verificationSecretPubKeySuffix) {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+		for _, k := range names {
+			pub, err := parsePublicKey(secret.Data[k])
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key %s in verification secret %s: %w", k, key, err)
+			}
+			v.publicKeys = append(v.publicKeys, pub)
+		}
+		if len(v.publicKeys) == 0 {
+			return nil, fmt.Errorf("verification secret %s has no *%s public keys", key, verificationSecretPubKeySuffix)
+		}
+	default:
+		return nil, fmt.Errorf("unknown verification mode %s", v.mode)
+	}
+
+	return v, nil
+}
+
+// provenance reports whether the .prov file of the chart must be pulled
+func (v *chartVerifier) provenance() bool {
+	return v != nil && v.mode == verificationModeProvenance
+}
+
+// verifyProvenance checks the .prov file next to the chart archive against the keyring
+func (v *chartVerifier) verifyProvenance(chartPath string) error {
+	if _, err := downloader.VerifyChart(chartPath, v.keyring); err != nil {
+		return &verificationError{err: err}
+	}
+	return nil
+}
+
+// verifyCosign checks that the chart manifest has a cosign signature made by one of the public keys
+func (v *chartVerifier) verifyCosign(ctx context.Context, a *repoAuth, name, manifestDigest string) error {
+	signatures, err := a.fetchCosignSignatures(ctx, name, manifestDigest)
+	if err != nil {
+		if errors.Is(err, errdef.ErrNotFound) {
+			return &verificationError{err: fmt.Errorf("no cosign signature found for %s@%s", name, manifestDigest)}
+		}
+		return fmt.Errorf("failed to fetch cosign signatures: %w", err)
+	}
+
+	var errs []string
+	for _, s := range signatures {
+		if err := v.verifyCosignSignature(s, manifestDigest); err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		return nil
+	}
+	if len(errs) == 0 {
+		errs = append(errs, "no signature layers")
+	}
+	return &verificationError{err: fmt.Errorf("no valid cosign signature for %s@%s: %s", name, manifestDigest, strings.Join(errs, "; "))}
+}
+
+// cosignSignature is a simple signing payload and its signature
+type cosignSignature struct {
+	payload   []byte
+	signature []byte
+}
+
+// cosignPayload is the part of the simple signing payload that binds the signature to the manifest
+type cosignPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+func (v *chartVerifier) verifyCosignSignature(s cosignSignature, manifestDigest string) error {
+	verified := false
+	for _, pub := range v.publicKeys {
+		if verifySignature(pub, s.payload, s.signature) == nil {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return fmt.Errorf("signature does not match any public key")
+	}
+
+	var payload cosignPayload
+	if err := json.Unmarshal(s.payload, &payload); err != nil {
+		return fmt.Errorf("invalid signature payload: %w", err)
+	}
+	if d := payload.Critical.Image.DockerManifestDigest; d != manifestDigest {
+		return fmt.Errorf("signature is for %s", d)
+	}
+	return nil
+}
+
+// fetchCosignSignatures fetches the signatures stored by cosign under the sha256-<digest>.sig tag
+func (a *repoAuth) fetchCosignSignatures(ctx context.Context, name, manifestDigest string) ([]cosignSignature, error) {
+	repo, err := remote.NewRepository(strings.TrimPrefix(name, fmt.Sprintf("%s://", registry.OCIScheme)))
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := http.DefaultClient
+	if a.hasTLSConfig() {
+		if httpClient, err = a.httpClient(); err != nil {
+			return nil, err
+		}
+	}
+	cred := auth.Credential{Username: a.username, Password: a.password}
+	if a.token != "" {
+		cred = auth.Credential{AccessToken: a.token}
+	}
+	repo.Client = &auth.Client{
+		Client:     httpClient,
+		Cache:      auth.NewCache(),
+		Credential: auth.StaticCredential(repo.Reference.Registry, cred),
+	}
+
+	tag := strings.Replace(manifestDigest, ":", "-", 1) + ".sig"
+	desc, rc, err := repo.FetchReference(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := content.ReadAll(rc, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid signature manifest: %w", err)
+	}
+
+	var signatures []cosignSignature
+	for _, layer := range manifest.Layers {
+		encoded, ok := layer.Annotations[cosignSignatureAnnotation]
+		if !ok {
+			continue
+		}
+		sig, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature encoding: %w", err)
+		}
+		payload, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, cosignSignature{payload: payload, signature: sig})
+	}
+	return signatures, nil
+}
+
+// parsePublicKey parses a PEM encoded PKIX public key
+func parsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
+
+// verifySignature verifies the signature of the payload like cosign does for the key type
+func verifySignature(pub crypto.PublicKey, payload, sig []byte) error {
+	sum := sha256.Sum256(payload)
+	switch k := pub.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(k, sum[:], sig) {
+			return fmt.Errorf("invalid ecdsa signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, sum[:], sig)
+	case ed25519.PublicKey:
+		if !ed25519.Verify(k, payload, sig) {
+			return fmt.Errorf("invalid ed25519 signature")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported public key type %T", pub)
+	}
+}
@@ -0,0 +1,402 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/crypto/openpgp"
+	"helm.sh/helm/v3/pkg/provenance"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+// newTestSigner returns a provenance signer and the keyring holding its public key
+func newTestSigner(t *testing.T) (*provenance.Signatory, []byte) {
+	t.Helper()
+
+	entity, err := openpgp.NewEntity("pluma", "test", "pluma@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to create pgp entity: %v", err)
+	}
+	var keyring bytes.Buffer
+	if err := entity.Serialize(&keyring); err != nil {
+		t.Fatalf("failed to serialize pgp entity: %v", err)
+	}
+	return &provenance.Signatory{Entity: entity}, keyring.Bytes()
+}
+
+// newTestECDSAKey returns an ECDSA key and its PEM encoded public key
+func newTestECDSAKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal public key: %v", err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestGetComponentVerification(t *testing.T) {
+	repoPolicy := &operatorv1alpha1.ChartVerification{Mode: verificationModeProvenance}
+	componentPolicy := &operatorv1alpha1.ChartVerification{Mode: verificationModeCosign}
+
+	tests := []struct {
+		name      string
+		component *operatorv1alpha1.HelmComponent
+		repo      *operatorv1alpha1.HelmRepo
+		expected  *operatorv1alpha1.ChartVerification
+	}{
+		{
+			name:      "no policy",
+			component: &operatorv1alpha1.HelmComponent{},
+			repo:      &operatorv1alpha1.HelmRepo{},
+			expected:  nil,
+		},
+		{
+			name:      "repo policy",
+			component: &operatorv1alpha1.HelmComponent{},
+			repo:      &operatorv1alpha1.HelmRepo{Verification: repoPolicy},
+			expected:  repoPolicy,
+		},
+		{
+			name:      "component policy overrides repo policy",
+			component: &operatorv1alpha1.HelmComponent{Verification: componentPolicy},
+			repo:      &operatorv1alpha1.HelmRepo{Verification: repoPolicy},
+			expected:  componentPolicy,
+		},
+		{
+			name:      "component policy without mode",
+			component: &operatorv1alpha1.HelmComponent{Verification: &operatorv1alpha1.ChartVerification{}},
+			repo:      &operatorv1alpha1.HelmRepo{Verification: repoPolicy},
+			expected:  repoPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getComponentVerification(tt.component, tt.repo); got != tt.expected {
+				t.Errorf("getComponentVerification() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHelmAppReconciler_newChartVerifier(t *testing.T) {
+	_, keyring := newTestSigner(t)
+	_, pub := newTestECDSAKey(t)
+
+	r := newFakeReconciler(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "keyring", Namespace: "istio-system"},
+			Data:       map[string][]byte{verificationSecretKeyringKey: keyring},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "cosign", Namespace: "istio-system"},
+			Data:       map[string][]byte{"cosign.pub": pub, "README": []byte("ignored")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "invalid", Namespace: "istio-system"},
+			Data:       map[string][]byte{"cosign.pub": []byte("not a key")},
+		},
+	)
+
+	tests := []struct {
+		name         string
+		policy       *operatorv1alpha1.ChartVerification
+		expectNil    bool
+		expectErr    bool
+		expectedKeys int
+	}{
+		{
+			name:      "no policy",
+			expectNil: true,
+		},
+		{
+			name:   "provenance",
+			policy: &operatorv1alpha1.ChartVerification{Mode: verificationModeProvenance, SecretRef: &operatorv1alpha1.SecretReference{Name: "keyring"}},
+		},
+		{
+			name:         "cosign",
+			policy:       &operatorv1alpha1.ChartVerification{Mode: verificationModeCosign, SecretRef: &operatorv1alpha1.SecretReference{Name: "cosign"}},
+			expectedKeys: 1,
+		},
+		{
+			name:      "missing secretRef",
+			policy:    &operatorv1alpha1.ChartVerification{Mode: verificationModeProvenance},
+			expectErr: true,
+		},
+		{
+			name:      "missing secret",
+			policy:    &operatorv1alpha1.ChartVerification{Mode: verificationModeProvenance, SecretRef: &operatorv1alpha1.SecretReference{Name: "not-found"}},
+			expectErr: true,
+		},
+		{
+			name:      "keyring missing from secret",
+			policy:    &operatorv1alpha1.ChartVerification{Mode: verificationModeProvenance, SecretRef: &operatorv1alpha1.SecretReference{Name: "cosign"}},
+			expectErr: true,
+		},
+		{
+			name:      "no public keys in secret",
+			policy:    &operatorv1alpha1.ChartVerification{Mode: verificationModeCosign, SecretRef: &operatorv1alpha1.SecretReference{Name: "keyring"}},
+			expectErr: true,
+		},
+		{
+			name:      "invalid public key",
+			policy:    &operatorv1alpha1.ChartVerification{Mode: verificationModeCosign, SecretRef: &operatorv1alpha1.SecretReference{Name: "invalid"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := &repoAuth{}
+			defer auth.cleanup(context.Background())
+
+			v, err := r.newChartVerifier(context.Background(), "istio-system", tt.policy, auth)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("newChartVerifier() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newChartVerifier() error = %v", err)
+			}
+			if tt.expectNil {
+				if v != nil {
+					t.Errorf("newChartVerifier() = %v, want nil", v)
+				}
+				return
+			}
+			if v.mode == verificationModeProvenance {
+				data, err := os.ReadFile(v.keyring)
+				if err != nil {
+					t.Fatalf("failed to read keyring: %v", err)
+				}
+				if !bytes.Equal(data, keyring) {
+					t.Errorf("keyring content mismatch")
+				}
+			}
+			if len(v.publicKeys) != tt.expectedKeys {
+				t.Errorf("publicKeys = %d, want %d", len(v.publicKeys), tt.expectedKeys)
+			}
+		})
+	}
+}
+
+func TestRepoAuth_locateChart_Provenance(t *testing.T) {
+	cache := settings.RepositoryCache
+	defer func() { settings.RepositoryCache = cache }()
+
+	signer, keyring := newTestSigner(t)
+	otherSigner, _ := newTestSigner(t)
+
+	tests := []struct {
+		name      string
+		signer    *provenance.Signatory
+		mode      string
+		expectErr bool
+	}{
+		{
+			name:   "signed by trusted key",
+			signer: signer,
+			mode:   verificationModeProvenance,
+		},
+		{
+			name:      "missing provenance",
+			mode:      verificationModeProvenance,
+			expectErr: true,
+		},
+		{
+			name:      "signed by untrusted key",
+			signer:    otherSigner,
+			mode:      verificationModeProvenance,
+			expectErr: true,
+		},
+		{
+			name:      "cosign requires an OCI chart",
+			signer:    signer,
+			mode:      verificationModeCosign,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings.RepositoryCache = t.TempDir()
+			repoURL, _ := newTestChartRepo(t, tt.signer)
+
+			auth := &repoAuth{url: repoURL}
+			defer auth.cleanup(context.Background())
+			keyringFile, err := auth.writeFile(verificationSecretKeyringKey, keyring)
+			if err != nil {
+				t.Fatalf("failed to write keyring: %v", err)
+			}
+			verifier := &chartVerifier{mode: tt.mode, keyring: keyringFile}
+
+			p, _, err := auth.locateChart(context.TODO(), "demo", "0.1.0", "", verifier, nil)
+			if tt.expectErr {
+				var vErr *verificationError
+				if !errors.As(err, &vErr) {
+					t.Fatalf("locateChart() error = %v, want verification error", err)
+				}
+				if _, err := os.Stat(filepath.Join(settings.RepositoryCache, "demo-0.1.0.tgz")); !os.IsNotExist(err) {
+					t.Errorf("chart archive should be removed after failed verification")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("locateChart() error = %v", err)
+			}
+			if _, err := os.Stat(p); err != nil {
+				t.Errorf("chart archive not found: %v", err)
+			}
+		})
+	}
+}
+
+func TestChartVerifier_verifyCosignSignature(t *testing.T) {
+	key, pub := newTestECDSAKey(t)
+	otherKey, _ := newTestECDSAKey(t)
+	publicKey, err := parsePublicKey(pub)
+	if err != nil {
+		t.Fatalf("parsePublicKey() error = %v", err)
+	}
+	v := &chartVerifier{mode: verificationModeCosign, publicKeys: []crypto.PublicKey{publicKey}}
+
+	manifestDigest := "sha256:3b1d1f3a7c1e9d3f7a0c2b4e6d8f0a1c3e5b7d9f1a3c5e7b9d1f3a5c7e9b1d3f"
+	payloadFor := func(d string) []byte {
+		return []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry.example.com/charts/demo"},`+
+			`"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`, d))
+	}
+	sign := func(k *ecdsa.PrivateKey, payload []byte) []byte {
+		sum := sha256.Sum256(payload)
+		sig, err := ecdsa.SignASN1(rand.Reader, k, sum[:])
+		if err != nil {
+			t.Fatalf("failed to sign payload: %v", err)
+		}
+		return sig
+	}
+
+	tests := []struct {
+		name      string
+		signature cosignSignature
+		expectErr bool
+	}{
+		{
+			name:      "valid signature",
+			signature: cosignSignature{payload: payloadFor(manifestDigest), signature: sign(key, payloadFor(manifestDigest))},
+		},
+		{
+			name:      "signed by untrusted key",
+			signature: cosignSignature{payload: payloadFor(manifestDigest), signature: sign(otherKey, payloadFor(manifestDigest))},
+			expectErr: true,
+		},
+		{
+			name: "signature for another manifest",
+			signature: cosignSignature{
+				payload:   payloadFor("sha256:0000000000000000000000000000000000000000000000000000000000000000"),
+				signature: sign(key, payloadFor("sha256:0000000000000000000000000000000000000000000000000000000000000000")),
+			},
+			expectErr: true,
+		},
+		{
+			name:      "tampered payload",
+			signature: cosignSignature{payload: append(payloadFor(manifestDigest), ' '), signature: sign(key, payloadFor(manifestDigest))},
+			expectErr: true,
+		},
+		{
+			name:      "invalid payload",
+			signature: cosignSignature{payload: []byte("not json"), signature: sign(key, []byte("not json"))},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.verifyCosignSignature(tt.signature, manifestDigest)
+			if tt.expectErr && err == nil {
+				t.Errorf("verifyCosignSignature() expected error")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("verifyCosignSignature() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifySignature_Ed25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	payload := []byte("payload")
+
+	if err := verifySignature(pub, payload, ed25519.Sign(priv, payload)); err != nil {
+		t.Errorf("verifySignature() error = %v", err)
+	}
+	if err := verifySignature(pub, []byte("other"), ed25519.Sign(priv, payload)); err == nil {
+		t.Errorf("verifySignature() expected error")
+	}
+}
+
+func TestRepoAuth_fetchCosignSignatures_BearerToken(t *testing.T) {
+	manifestDigest := "sha256:3b1d1f3a7c1e9d3f7a0c2b4e6d8f0a1c3e5b7d9f1a3c5e7b9d1f3a5c7e9b1d3f"
+	payload := []byte(`{"critical":{"image":{"docker-manifest-digest":"` + manifestDigest + `"}}}`)
+	signature := []byte("signature")
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers: []ocispec.Descriptor{{
+			MediaType:   "application/vnd.dev.cosign.simplesigning.v1+json",
+			Digest:      digest.FromBytes(payload),
+			Size:        int64(len(payload)),
+			Annotations: map[string]string{cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature)},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal signature manifest: %v", err)
+	}
+	server := newTestBearerRegistry(t, "t0ken", map[string]testRegistryFile{
+		"/v2/istio/base/manifests/" + strings.Replace(manifestDigest, ":", "-", 1) + ".sig": {
+			mediaType: ocispec.MediaTypeImageManifest,
+			data:      manifest,
+		},
+		"/v2/istio/base/blobs/" + digest.FromBytes(payload).String(): {mediaType: "application/octet-stream", data: payload},
+	})
+
+	auth := &repoAuth{token: "t0ken", insecureSkipTLSVerify: true}
+	defer auth.cleanup(context.Background())
+	signatures, err := auth.fetchCosignSignatures(context.TODO(), "oci://"+server.Listener.Addr().String()+"/istio/base", manifestDigest)
+	if err != nil {
+		t.Fatalf("fetchCosignSignatures() error = %v", err)
+	}
+	if len(signatures) != 1 || !bytes.Equal(signatures[0].payload, payload) || !bytes.Equal(signatures[0].signature, signature) {
+		t.Errorf("fetchCosignSignatures() = %v, want the signature of the manifest", signatures)
+	}
+}
@@ -11,6 +11,9 @@ type IOPComponent struct {
 	HelmChartName string
 	// Compatible with istio legacy, the root node of component values
 	HelmBaseRootKey string
+	// K8sMappings map the k8s settings of the component to the values of its chart, keyed by their field.
+	// The settings without a mapping are not supported by the chart.
+	K8sMappings map[string]k8sMapping
 }
 
 const (
@@ -47,8 +50,15 @@ func getComponent(specName string) IOPComponent {
 			switch specName {
 			case ingressSpecName, egressSpecName:
 				comp.HelmChartName = "gateway"
-			case pilotSpecName, cniSpecName:
+				comp.K8sMappings = gatewayK8sMappings
+			case pilotSpecName:
 				comp.HelmBaseRootKey = c.ToHelmValuesTreeRoot
+				comp.K8sMappings = pilotK8sMappings
+			case cniSpecName:
+				comp.HelmBaseRootKey = c.ToHelmValuesTreeRoot
+				comp.K8sMappings = cniK8sMappings
+			case ztunnelSpecName:
+				comp.K8sMappings = ztunnelK8sMappings
 			}
 
 			return comp
@@ -57,3 +67,11 @@ func getComponent(specName string) IOPComponent {
 
 	return IOPComponent{}
 }
+
+// defaultGatewayName is the name of the gateway of a profile which does not name it
+func defaultGatewayName(c render.ComponentMigration) string {
+	if isEgressGateway(c) {
+		return "istio-egressgateway"
+	}
+	return "istio-ingressgateway"
+}
@@ -0,0 +1,144 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"istio.io/istio/operator/pkg/component"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// revisionLabel is set by the injector on the pods, `default` for the control plane without revision
+	revisionLabel   = "istio.io/rev"
+	defaultRevision = "default"
+	// injectTemplatesAnnotation of the gateway pods, those of the HelmApp are removed with the control plane
+	injectTemplatesAnnotation = "inject.istio.io/templates"
+	gatewayTemplate           = "gateway"
+
+	maxSampleWorkloads = 5
+)
+
+// proxyUsage is the pods whose injected proxies use a control plane
+type proxyUsage struct {
+	count int
+	// samples are the workloads of the first pods, e.g. `bookinfo/ReplicaSet/reviews-v1-5d7f8d7c9`
+	samples []string
+}
+
+// proxiesOfControlPlane returns the pods injected by the istiod of the HelmApp, nothing when it does
+// not install istiod. Only the metadata of the pods are listed.
+func (r *IstioOperatorReconciler) proxiesOfControlPlane(ctx context.Context, iop *istiov1alpha1.IstioOperator,
+	helmApp *operatorv1alpha1.HelmApp) (*proxyUsage, error) {
+	installsPilot := false
+	for _, name := range iopComponentNames(helmApp) {
+		if name == string(component.PilotComponentName) {
+			installsPilot = true
+			break
+		}
+	}
+	if !installsPilot {
+		return &proxyUsage{}, nil
+	}
+
+	revision := iop.Spec.GetRevision()
+	if revision == "" {
+		revision = defaultRevision
+	}
+	pods := &metav1.PartialObjectMetadataList{}
+	pods.SetGroupVersionKind(schema.GroupVersionKind{Version: "v1", Kind: "PodList"})
+	if err := r.List(ctx, pods, client.MatchingLabels{revisionLabel: revision}); err != nil {
+		return nil, fmt.Errorf("failed to list pods of revision %s: %w", revision, err)
+	}
+
+	gateways := gatewaysOfHelmApp(helmApp)
+	usage := &proxyUsage{}
+	seen := map[string]bool{}
+	for _, pod := range pods.Items {
+		if isGatewayOf(&pod, gateways) {
+			continue
+		}
+		usage.count++
+		workload := pod.GetNamespace() + "/Pod/" + pod.GetName()
+		if owners := pod.GetOwnerReferences(); len(owners) > 0 {
+			workload = pod.GetNamespace() + "/" + owners[0].Kind + "/" + owners[0].Name
+		}
+		if !seen[workload] {
+			seen[workload] = true
+			usage.samples = append(usage.samples, workload)
+		}
+	}
+	sort.Strings(usage.samples)
+	if len(usage.samples) > maxSampleWorkloads {
+		usage.samples = usage.samples[:maxSampleWorkloads]
+	}
+	return usage, nil
+}
+
+// gatewayPods selects the pods of a gateway release by the labels of the gateway chart
+type gatewayPods struct {
+	namespace string
+	app       string
+	istio     string
+}
+
+// gatewaysOfHelmApp returns the pods selectors of the gateway releases of the HelmApp, the gateway chart
+// defaults the `app` label to the release name and the `istio` label to it without the `istio-` prefix
+func gatewaysOfHelmApp(helmApp *operatorv1alpha1.HelmApp) []gatewayPods {
+	var gateways []gatewayPods
+	for _, c := range helmApp.Spec.GetComponents() {
+		if c.GetChart() != "gateway" {
+			continue
+		}
+		gw := gatewayPods{namespace: c.GetNamespace(), app: c.GetName(), istio: strings.TrimPrefix(c.GetName(), "istio-")}
+		if gw.namespace == "" {
+			gw.namespace = helmApp.GetNamespace()
+		}
+		if labels := c.GetComponentValues().GetFields()["labels"].GetStructValue(); labels != nil {
+			if app := labels.GetFields()["app"].GetStringValue(); app != "" {
+				gw.app = app
+			}
+			if istio := labels.GetFields()["istio"].GetStringValue(); istio != "" {
+				gw.istio = istio
+			}
+		}
+		gateways = append(gateways, gw)
+	}
+	return gateways
+}
+
+// isGatewayOf reports whether the pod is injected with the gateway template and belongs to one of the gateways,
+// the gateways deployed by users in their namespaces still block the deletion
+func isGatewayOf(pod *metav1.PartialObjectMetadata, gateways []gatewayPods) bool {
+	if pod.GetAnnotations()[injectTemplatesAnnotation] != gatewayTemplate {
+		return false
+	}
+	for _, gw := range gateways {
+		if pod.GetNamespace() == gw.namespace && pod.GetLabels()["app"] == gw.app && pod.GetLabels()["istio"] == gw.istio {
+			return true
+		}
+	}
+	return false
+}
+
+// deletionBlockedMessage explains why the deletion of the IstioOperator waits and how to force it
+func deletionBlockedMessage(iop *istiov1alpha1.IstioOperator, usage *proxyUsage) string {
+	revision := iop.Spec.GetRevision()
+	if revision == "" {
+		revision = defaultRevision
+	}
+	return fmt.Sprintf("Deletion is blocked: %d pods still use the control plane of revision %s, e.g. %s. "+
+		"Move them to another revision or annotate the IstioOperator with %s=true to delete it anyway",
+		usage.count, revision, strings.Join(usage.samples, ", "), constants.ForceDeleteAnnotation)
+}
+
+func isForceDelete(iop *istiov1alpha1.IstioOperator) bool {
+	return iop.GetAnnotations()[constants.ForceDeleteAnnotation] == "true"
+}
@@ -0,0 +1,134 @@
+package istio
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIstioOperatorReconciler_reconcileDelete(t *testing.T) {
+	now := metav1.Now()
+	helmApp := &operatorv1alpha1.HelmApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "istio",
+			Namespace:   "istio-system",
+			Labels:      map[string]string{constants.ManagedLabel: constants.ManagedLabelValue},
+			Annotations: map[string]string{constants.IOPComponentsAnnotation: `{"iop-istio-istiod":"Pilot"}`},
+		},
+		Spec: &operatorv1alpha1.HelmAppSpec{
+			Components: []*operatorv1alpha1.HelmComponent{
+				{Name: "iop-istio-istiod", Chart: "istiod"},
+				{Name: "istio-ingressgateway", Chart: "gateway"},
+			},
+		},
+	}
+	newPod := func(name, revision string, annotations map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "bookinfo",
+				Labels:      map[string]string{revisionLabel: revision, "app": strings.TrimSuffix(name, "-0")},
+				Annotations: annotations,
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: "apps/v1", Kind: "ReplicaSet", Name: strings.TrimSuffix(name, "-0"), UID: "uid"},
+				},
+			},
+		}
+	}
+	newGatewayPod := func(namespace, revision string) *corev1.Pod {
+		pod := newPod("istio-ingressgateway-0", revision, map[string]string{injectTemplatesAnnotation: gatewayTemplate})
+		pod.Namespace = namespace
+		pod.Labels["istio"] = "ingressgateway"
+		return pod
+	}
+
+	tests := []struct {
+		name     string
+		iop      *istiov1alpha1.IstioOperator
+		pods     []runtime.Object
+		blocked  bool
+		contains string
+	}{
+		{
+			name: "no proxies",
+			iop:  newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{}),
+			pods: []runtime.Object{newPod("reviews-0", "canary", nil)},
+		},
+		{
+			name: "proxies of the control plane",
+			iop:  newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{}),
+			pods: []runtime.Object{
+				newPod("reviews-0", defaultRevision, nil),
+				newPod("ratings-0", defaultRevision, nil),
+				newGatewayPod("istio-system", defaultRevision),
+			},
+			blocked:  true,
+			contains: "2 pods still use the control plane of revision default, e.g. bookinfo/ReplicaSet/ratings, bookinfo/ReplicaSet/reviews",
+		},
+		{
+			name: "gateway pods of the HelmApp",
+			iop:  newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{}),
+			pods: []runtime.Object{newGatewayPod("istio-system", defaultRevision)},
+		},
+		{
+			name:     "gateway pods deployed by users",
+			iop:      newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{}),
+			pods:     []runtime.Object{newGatewayPod("bookinfo", defaultRevision)},
+			blocked:  true,
+			contains: "1 pods still use the control plane of revision default, e.g. bookinfo/ReplicaSet/istio-ingressgateway",
+		},
+		{
+			name:     "proxies of the revision",
+			iop:      newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Revision: "canary"}),
+			pods:     []runtime.Object{newPod("reviews-0", "canary", nil)},
+			blocked:  true,
+			contains: "1 pods still use the control plane of revision canary",
+		},
+		{
+			name: "force delete",
+			iop: newTestIop("istio", map[string]string{constants.ForceDeleteAnnotation: "true"},
+				&istiov1alpha1.IstioOperatorSpec{}),
+			pods: []runtime.Object{newPod("reviews-0", defaultRevision, nil)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.iop.Finalizers = []string{constants.IOPFinalizer}
+			tt.iop.DeletionTimestamp = &now
+			objs := append([]runtime.Object{tt.iop, helmApp.DeepCopy()}, tt.pods...)
+			r := newFakeReconciler(objs...)
+			ctx := context.Background()
+
+			if _, err := r.reconcileDelete(ctx, tt.iop); err != nil {
+				t.Fatalf("reconcileDelete() error = %v", err)
+			}
+
+			err := r.Get(ctx, client.ObjectKeyFromObject(helmApp), &operatorv1alpha1.HelmApp{})
+			if tt.blocked {
+				if err != nil {
+					t.Errorf("reconcileDelete() deleted the HelmApp, get error = %v", err)
+				}
+				if tt.iop.Status.GetStatus() != istiov1alpha1.InstallStatus_ACTION_REQUIRED {
+					t.Errorf("reconcileDelete() status = %v, want ACTION_REQUIRED", tt.iop.Status.GetStatus())
+				}
+				if !strings.Contains(tt.iop.Status.GetMessage(), tt.contains) {
+					t.Errorf("reconcileDelete() message = %q, want it to contain %q", tt.iop.Status.GetMessage(), tt.contains)
+				}
+				return
+			}
+			if !errors.IsNotFound(err) {
+				t.Errorf("reconcileDelete() kept the HelmApp, get error = %v", err)
+			}
+		})
+	}
+}
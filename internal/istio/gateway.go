@@ -0,0 +1,93 @@
+package istio
+
+import "istio.io/istio/operator/pkg/apis"
+
+// gatewayK8sMappings are the k8s settings of the IstioOperator gateways supported by the gateway chart,
+// keyed by their field
+var gatewayK8sMappings = map[string]k8sMapping{
+	"affinity": setValue("affinity", func(k8s *apis.KubernetesResources) any { return k8s.Affinity }),
+	"env":      mapEnv,
+	"hpaSpec": mapHpaSpec(hpaKeys{
+		minReplicas: "autoscaling.minReplicas",
+		maxReplicas: "autoscaling.maxReplicas",
+		cpu:         "autoscaling.targetCPUUtilizationPercentage",
+		memory:      "autoscaling.targetMemoryUtilizationPercentage",
+		behavior:    "autoscaling.autoscaleBehavior",
+	}),
+	"imagePullPolicy": setValue("imagePullPolicy", func(k8s *apis.KubernetesResources) any { return k8s.ImagePullPolicy }),
+	"nodeSelector":    setValue("nodeSelector", func(k8s *apis.KubernetesResources) any { return k8s.NodeSelector }),
+	"podAnnotations":  setValue("podAnnotations", func(k8s *apis.KubernetesResources) any { return k8s.PodAnnotations }),
+	"podDisruptionBudget": func(k8s *apis.KubernetesResources, values map[string]interface{}) []string {
+		pdb, ok := toValue(k8s.PodDisruptionBudget).(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		// The selector of the gateway pods is set by the chart
+		var unsupported []string
+		if _, ok := pdb["selector"]; ok {
+			delete(pdb, "selector")
+			unsupported = append(unsupported, "selector")
+		}
+		values["podDisruptionBudget"] = pdb
+		return unsupported
+	},
+	"priorityClassName":  setValue("priorityClassName", func(k8s *apis.KubernetesResources) any { return k8s.PriorityClassName }),
+	"readinessProbe":     setValue("readinessProbe", func(k8s *apis.KubernetesResources) any { return k8s.ReadinessProbe }),
+	"replicaCount":       setValue("replicaCount", func(k8s *apis.KubernetesResources) any { return k8s.ReplicaCount }),
+	"resources":          setValue("resources", func(k8s *apis.KubernetesResources) any { return k8s.Resources }),
+	"securityContext":    setValue("securityContext", func(k8s *apis.KubernetesResources) any { return k8s.SecurityContext }),
+	"service":            mapGatewayService,
+	"serviceAnnotations": mapGatewayServiceAnnotations,
+	"strategy":           setValue("strategy", func(k8s *apis.KubernetesResources) any { return k8s.Strategy }),
+	"tolerations":        setValue("tolerations", func(k8s *apis.KubernetesResources) any { return k8s.Tolerations }),
+	"volumeMounts":       setValue("volumeMounts", func(k8s *apis.KubernetesResources) any { return k8s.VolumeMounts }),
+	"volumes":            setValue("volumes", func(k8s *apis.KubernetesResources) any { return k8s.Volumes }),
+}
+
+// gatewayServiceFields are the fields of the service spec with a value in the gateway chart
+var gatewayServiceFields = map[string]bool{
+	"type":                          true,
+	"ports":                         true,
+	"loadBalancerIP":                true,
+	"loadBalancerSourceRanges":      true,
+	"externalTrafficPolicy":         true,
+	"externalIPs":                   true,
+	"ipFamilyPolicy":                true,
+	"ipFamilies":                    true,
+	"allocateLoadBalancerNodePorts": true,
+}
+
+// mapGatewayService maps the service spec, the selector is the one of the chart
+func mapGatewayService(k8s *apis.KubernetesResources, values map[string]interface{}) []string {
+	var unsupported []string
+	fields, _ := toValue(k8s.Service).(map[string]interface{})
+	if len(fields) == 0 {
+		return nil
+	}
+	service := serviceValues(values)
+	for field, value := range fields {
+		if !gatewayServiceFields[field] {
+			unsupported = append(unsupported, field)
+			continue
+		}
+		service[field] = value
+	}
+	return unsupported
+}
+
+func mapGatewayServiceAnnotations(k8s *apis.KubernetesResources, values map[string]interface{}) []string {
+	if len(k8s.ServiceAnnotations) > 0 {
+		serviceValues(values)["annotations"] = toValue(k8s.ServiceAnnotations)
+	}
+	return nil
+}
+
+// serviceValues returns the service values of the chart, shared by the service and its annotations
+func serviceValues(values map[string]interface{}) map[string]interface{} {
+	service, ok := values["service"].(map[string]interface{})
+	if !ok {
+		service = map[string]interface{}{}
+		values["service"] = service
+	}
+	return service
+}
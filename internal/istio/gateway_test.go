@@ -0,0 +1,503 @@
+package istio
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"istio.io/istio/operator/pkg/apis"
+	"istio.io/istio/operator/pkg/values"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"sigs.k8s.io/yaml"
+)
+
+// newGatewaySpec builds the spec of a gateway the way the components of an IstioOperator are read
+func newGatewaySpec(t *testing.T, spec string) apis.GatewayComponentSpec {
+	t.Helper()
+	m, err := values.MapFromYaml([]byte(spec))
+	if err != nil {
+		t.Fatalf("failed to parse gateway spec: %v", err)
+	}
+	gw, err := values.ConvertMap[apis.GatewayComponentSpec](m)
+	if err != nil {
+		t.Fatalf("failed to convert gateway spec: %v", err)
+	}
+	gw.Raw = m
+	return gw
+}
+
+func TestGatewayK8sValues(t *testing.T) {
+	tests := []struct {
+		name                string
+		spec                string
+		expected            map[string]interface{}
+		expectedUnsupported []string
+	}{
+		{
+			name:     "no k8s settings",
+			spec:     `name: istio-ingressgateway`,
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "affinity",
+			spec: `
+k8s:
+  affinity:
+    nodeAffinity:
+      requiredDuringSchedulingIgnoredDuringExecution:
+        nodeSelectorTerms:
+        - matchExpressions:
+          - key: kubernetes.io/arch
+            operator: In
+            values: [amd64]
+`,
+			expected: map[string]interface{}{
+				"affinity": map[string]interface{}{
+					"nodeAffinity": map[string]interface{}{
+						"requiredDuringSchedulingIgnoredDuringExecution": map[string]interface{}{
+							"nodeSelectorTerms": []interface{}{
+								map[string]interface{}{
+									"matchExpressions": []interface{}{
+										map[string]interface{}{"key": "kubernetes.io/arch", "operator": "In", "values": []interface{}{"amd64"}},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "env values, valueFrom is unsupported",
+			spec: `
+k8s:
+  env:
+  - name: ISTIO_META_ROUTER_MODE
+    value: standard
+  - name: POD_IP
+    valueFrom:
+      fieldRef:
+        fieldPath: status.podIP
+`,
+			expected: map[string]interface{}{
+				"env": map[string]interface{}{"ISTIO_META_ROUTER_MODE": "standard"},
+			},
+			expectedUnsupported: []string{"k8s.env.POD_IP.valueFrom"},
+		},
+		{
+			name: "hpaSpec",
+			spec: `
+k8s:
+  hpaSpec:
+    minReplicas: 2
+    maxReplicas: 5
+    metrics:
+    - type: Resource
+      resource:
+        name: cpu
+        target:
+          type: Utilization
+          averageUtilization: 60
+    - type: Resource
+      resource:
+        name: memory
+        target:
+          type: Utilization
+          averageUtilization: 70
+    - type: Pods
+      pods:
+        metric:
+          name: requests
+        target:
+          type: AverageValue
+          averageValue: "10"
+    behavior:
+      scaleDown:
+        stabilizationWindowSeconds: 300
+`,
+			expected: map[string]interface{}{
+				"autoscaling": map[string]interface{}{
+					"minReplicas":                       int64(2),
+					"maxReplicas":                       int64(5),
+					"targetCPUUtilizationPercentage":    int64(60),
+					"targetMemoryUtilizationPercentage": int64(70),
+					"autoscaleBehavior": map[string]interface{}{
+						"scaleDown": map[string]interface{}{"stabilizationWindowSeconds": int64(300)},
+					},
+				},
+			},
+			expectedUnsupported: []string{"k8s.hpaSpec.metrics[2]"},
+		},
+		{
+			name: "imagePullPolicy",
+			spec: `
+k8s:
+  imagePullPolicy: Always
+`,
+			expected: map[string]interface{}{"imagePullPolicy": "Always"},
+		},
+		{
+			name: "nodeSelector",
+			spec: `
+k8s:
+  nodeSelector:
+    node-role: ingress
+`,
+			expected: map[string]interface{}{"nodeSelector": map[string]interface{}{"node-role": "ingress"}},
+		},
+		{
+			name: "podAnnotations",
+			spec: `
+k8s:
+  podAnnotations:
+    prometheus.io/scrape: "true"
+`,
+			expected: map[string]interface{}{"podAnnotations": map[string]interface{}{"prometheus.io/scrape": "true"}},
+		},
+		{
+			name: "podDisruptionBudget without its selector",
+			spec: `
+k8s:
+  podDisruptionBudget:
+    minAvailable: 1
+    selector:
+      matchLabels:
+        app: istio-ingressgateway
+`,
+			expected: map[string]interface{}{
+				"podDisruptionBudget": map[string]interface{}{"minAvailable": int64(1)},
+			},
+			expectedUnsupported: []string{"k8s.podDisruptionBudget.selector"},
+		},
+		{
+			name: "priorityClassName",
+			spec: `
+k8s:
+  priorityClassName: system-cluster-critical
+`,
+			expected: map[string]interface{}{"priorityClassName": "system-cluster-critical"},
+		},
+		{
+			name: "readinessProbe",
+			spec: `
+k8s:
+  readinessProbe:
+    failureThreshold: 30
+    httpGet:
+      path: /healthz/ready
+      port: 15021
+`,
+			expected: map[string]interface{}{
+				"readinessProbe": map[string]interface{}{
+					"failureThreshold": int64(30),
+					"httpGet":          map[string]interface{}{"path": "/healthz/ready", "port": int64(15021)},
+				},
+			},
+		},
+		{
+			name: "replicaCount",
+			spec: `
+k8s:
+  replicaCount: 3
+`,
+			expected: map[string]interface{}{"replicaCount": int64(3)},
+		},
+		{
+			name: "resources",
+			spec: `
+k8s:
+  resources:
+    requests:
+      cpu: 100m
+      memory: 128Mi
+    limits:
+      cpu: "2"
+`,
+			expected: map[string]interface{}{
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{"cpu": "100m", "memory": "128Mi"},
+					"limits":   map[string]interface{}{"cpu": "2"},
+				},
+			},
+		},
+		{
+			name: "securityContext",
+			spec: `
+k8s:
+  securityContext:
+    runAsUser: 1337
+    runAsNonRoot: true
+`,
+			expected: map[string]interface{}{
+				"securityContext": map[string]interface{}{"runAsUser": int64(1337), "runAsNonRoot": true},
+			},
+		},
+		{
+			name: "service and serviceAnnotations, clusterIP is unsupported",
+			spec: `
+k8s:
+  service:
+    type: LoadBalancer
+    loadBalancerIP: 10.0.0.10
+    clusterIP: 10.96.0.10
+    externalTrafficPolicy: Local
+    ports:
+    - name: http2
+      port: 80
+      targetPort: 8080
+  serviceAnnotations:
+    service.beta.kubernetes.io/aws-load-balancer-type: nlb
+`,
+			expected: map[string]interface{}{
+				"service": map[string]interface{}{
+					"type":                  "LoadBalancer",
+					"loadBalancerIP":        "10.0.0.10",
+					"externalTrafficPolicy": "Local",
+					"ports": []interface{}{
+						map[string]interface{}{"name": "http2", "port": int64(80), "targetPort": int64(8080)},
+					},
+					"annotations": map[string]interface{}{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+				},
+			},
+			expectedUnsupported: []string{"k8s.service.clusterIP"},
+		},
+		{
+			name: "strategy",
+			spec: `
+k8s:
+  strategy:
+    rollingUpdate:
+      maxSurge: 100%
+      maxUnavailable: 25%
+`,
+			expected: map[string]interface{}{
+				"strategy": map[string]interface{}{
+					"rollingUpdate": map[string]interface{}{"maxSurge": "100%", "maxUnavailable": "25%"},
+				},
+			},
+		},
+		{
+			name: "tolerations",
+			spec: `
+k8s:
+  tolerations:
+  - key: dedicated
+    operator: Equal
+    value: ingress
+    effect: NoSchedule
+`,
+			expected: map[string]interface{}{
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "dedicated", "operator": "Equal", "value": "ingress", "effect": "NoSchedule"},
+				},
+			},
+		},
+		{
+			name: "volumes and volumeMounts",
+			spec: `
+k8s:
+  volumes:
+  - name: certs
+    secret:
+      secretName: gateway-certs
+  volumeMounts:
+  - name: certs
+    mountPath: /etc/certs
+`,
+			expected: map[string]interface{}{
+				"volumes": []interface{}{
+					map[string]interface{}{"name": "certs", "secret": map[string]interface{}{"secretName": "gateway-certs"}},
+				},
+				"volumeMounts": []interface{}{
+					map[string]interface{}{"name": "certs", "mountPath": "/etc/certs"},
+				},
+			},
+		},
+		{
+			name: "fields without a value in the chart",
+			spec: `
+k8s:
+  replicaCount: 2
+  readinessProbe: {}
+  unknownField: value
+`,
+			expected: map[string]interface{}{
+				"replicaCount":   int64(2),
+				"readinessProbe": map[string]interface{}{},
+			},
+			expectedUnsupported: []string{"k8s.unknownField"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unsupported := k8sValues(newGatewaySpec(t, tt.spec), gatewayK8sMappings)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("k8sValues() values = %v, want %v", got, tt.expected)
+			}
+			if !reflect.DeepEqual(unsupported, tt.expectedUnsupported) {
+				t.Errorf("k8sValues() unsupported = %v, want %v", unsupported, tt.expectedUnsupported)
+			}
+		})
+	}
+}
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_GatewayK8s(t *testing.T) {
+	iop := &istiov1alpha1.IstioOperator{}
+	if err := yaml.Unmarshal([]byte(`
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  name: istio
+  namespace: istio-system
+spec:
+  profile: minimal
+  components:
+    ingressGateways:
+    - name: istio-ingressgateway
+      enabled: true
+      k8s:
+        hpaSpec:
+          maxReplicas: 5
+        service:
+          type: NodePort
+          clusterIP: 10.96.0.10
+  values:
+    gateways:
+      istio-ingressgateway:
+        autoscaleEnabled: true
+`), iop); err != nil {
+		t.Fatalf("failed to unmarshal IstioOperator: %v", err)
+	}
+
+	helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), iop)
+	if err != nil {
+		t.Fatalf("convertIopToHelmApp() error = %v", err)
+	}
+
+	var gateway *operatorv1alpha1.HelmComponent
+	for _, c := range helmApp.Spec.Components {
+		if c.Name == "istio-ingressgateway" {
+			gateway = c
+		}
+	}
+	if gateway == nil {
+		t.Fatalf("convertIopToHelmApp() components = %v, want istio-ingressgateway", helmApp.Spec.Components)
+	}
+	values := gateway.ComponentValues.AsMap()
+	expectedAutoscaling := map[string]interface{}{"enabled": true, "maxReplicas": float64(5)}
+	if !reflect.DeepEqual(values["autoscaling"], expectedAutoscaling) {
+		t.Errorf("convertIopToHelmApp() autoscaling = %v, want %v", values["autoscaling"], expectedAutoscaling)
+	}
+	expectedService := map[string]interface{}{"type": "NodePort"}
+	if !reflect.DeepEqual(values["service"], expectedService) {
+		t.Errorf("convertIopToHelmApp() service = %v, want %v", values["service"], expectedService)
+	}
+
+	expectedUnsupported := `{"istio-ingressgateway":["k8s.service.clusterIP"]}`
+	if got := helmApp.Annotations[constants.IOPUnsupportedAnnotation]; got != expectedUnsupported {
+		t.Errorf("convertIopToHelmApp() unsupported = %s, want %s", got, expectedUnsupported)
+	}
+}
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_GatewayAutoscaling(t *testing.T) {
+	tests := []struct {
+		name     string
+		k8s      string
+		values   string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "hpaSpec without autoscaleEnabled",
+			k8s:      "{hpaSpec: {maxReplicas: 5}}",
+			values:   "{}",
+			expected: map[string]interface{}{"enabled": true, "maxReplicas": float64(5)},
+		},
+		{
+			name:     "hpaSpec with autoscaleEnabled false",
+			k8s:      "{hpaSpec: {maxReplicas: 5}}",
+			values:   "{autoscaleEnabled: false}",
+			expected: map[string]interface{}{"enabled": false, "maxReplicas": float64(5)},
+		},
+		{
+			name:     "autoscaleEnabled without hpaSpec",
+			k8s:      "{replicaCount: 2}",
+			values:   "{autoscaleEnabled: true}",
+			expected: map[string]interface{}{"enabled": true},
+		},
+		{
+			name:     "neither hpaSpec nor autoscaleEnabled",
+			k8s:      "{replicaCount: 2}",
+			values:   "{}",
+			expected: map[string]interface{}{"enabled": false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iop := &istiov1alpha1.IstioOperator{}
+			if err := yaml.Unmarshal([]byte(fmt.Sprintf(`
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  name: istio
+  namespace: istio-system
+spec:
+  profile: minimal
+  components:
+    ingressGateways:
+    - name: istio-ingressgateway
+      enabled: true
+      k8s: %s
+  values:
+    gateways:
+      istio-ingressgateway: %s
+`, tt.k8s, tt.values)), iop); err != nil {
+				t.Fatalf("failed to unmarshal IstioOperator: %v", err)
+			}
+
+			helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), iop)
+			if err != nil {
+				t.Fatalf("convertIopToHelmApp() error = %v", err)
+			}
+			for _, c := range helmApp.Spec.Components {
+				if c.Name != "istio-ingressgateway" {
+					continue
+				}
+				if got := c.ComponentValues.AsMap()["autoscaling"]; !reflect.DeepEqual(got, tt.expected) {
+					t.Errorf("convertIopToHelmApp() autoscaling = %v, want %v", got, tt.expected)
+				}
+				return
+			}
+			t.Fatalf("convertIopToHelmApp() components = %v, want istio-ingressgateway", helmApp.Spec.Components)
+		})
+	}
+}
+
+func TestInstallStatusFromHelmApp_Unsupported(t *testing.T) {
+	helmApp := &operatorv1alpha1.HelmApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "istio",
+			Namespace: "istio-system",
+			Annotations: map[string]string{
+				constants.IOPUnsupportedAnnotation: `{"istio-ingressgateway":["k8s.service.clusterIP","k8s.unknownField"],"internal-ingressgateway":["k8s.env.POD_IP.valueFrom"]}`,
+			},
+		},
+		Spec: &operatorv1alpha1.HelmAppSpec{
+			Components: []*operatorv1alpha1.HelmComponent{
+				{Name: "istio-ingressgateway", Chart: "gateway"},
+				{Name: "internal-ingressgateway", Chart: "gateway"},
+			},
+		},
+	}
+
+	expected := "Components are installed by HelmApp istio-system/istio; unsupported settings are ignored, " +
+		"internal-ingressgateway: k8s.env.POD_IP.valueFrom; istio-ingressgateway: k8s.service.clusterIP, k8s.unknownField"
+	if got := installStatusFromHelmApp(helmApp, nil).Message; got != expected {
+		t.Errorf("installStatusFromHelmApp() message = %q, want %q", got, expected)
+	}
+}
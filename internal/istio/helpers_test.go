@@ -0,0 +1,31 @@
+package istio
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeReconciler(objs ...runtime.Object) *IstioOperatorReconciler {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = istiov1alpha1.AddToScheme(scheme)
+	_ = operatorv1alpha1.AddToScheme(scheme)
+	return &IstioOperatorReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objs...).
+			WithStatusSubresource(&istiov1alpha1.IstioOperator{}, &operatorv1alpha1.HelmApp{}).Build(),
+		Scheme: scheme,
+	}
+}
+
+// newTestIop returns the IstioOperator istio-system/<name> with the annotations and the spec
+func newTestIop(name string, annotations map[string]string, spec *istiov1alpha1.IstioOperatorSpec) *istiov1alpha1.IstioOperator {
+	return &istiov1alpha1.IstioOperator{
+		TypeMeta:   metav1.TypeMeta{Kind: "IstioOperator", APIVersion: "install.istio.io/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "istio-system", Annotations: annotations},
+		Spec:       spec,
+	}
+}
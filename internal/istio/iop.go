@@ -2,19 +2,24 @@ package istio
 
 import (
 	"context"
+	errors2 "errors"
 	"fmt"
 	"os"
 	"reflect"
 	"time"
 
-	"istio.io/istio/operator/pkg/apis"
 	"istio.io/istio/operator/pkg/render"
+	"istio.io/istio/operator/pkg/values"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"pluma.io/pluma-operator/config"
 	"pluma.io/pluma-operator/internal/pkg/constants"
+	"pluma.io/pluma-operator/internal/pkg/tools"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/yaml"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -36,13 +41,38 @@ type IstioOperatorReconciler struct {
 	Config config.Config
 }
 
-// SetupWithManager sets up the controller with the Manager.
+// SetupWithManager sets up the controller with the Manager. The changes of the generated HelmApps reconcile
+// their IstioOperator, the ones of the HelmApp of the shared base all of them, so that it is restored.
 func (r *IstioOperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&istiov1alpha1.IstioOperator{}).
+		Watches(&operatorv1alpha1.HelmApp{}, handler.EnqueueRequestsFromMapFunc(r.istioOperatorsOfHelmApp)).
 		Complete(r)
 }
 
+// istioOperatorsOfHelmApp maps a HelmApp to the IstioOperators it is generated from
+func (r *IstioOperatorReconciler) istioOperatorsOfHelmApp(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetLabels()[constants.ManagedLabel] != constants.ManagedLabelValue {
+		return nil
+	}
+	if name := obj.GetLabels()[constants.SourceFromIOP]; name != "" {
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+	}
+	if obj.GetLabels()[constants.IOPClusterComponentsLabel] != "true" {
+		return nil
+	}
+	iops := &istiov1alpha1.IstioOperatorList{}
+	if err := r.List(ctx, iops); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list IstioOperators")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(iops.Items))
+	for _, iop := range iops.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(iop)})
+	}
+	return requests
+}
+
 func (r *IstioOperatorReconciler) reconcileDelete(ctx context.Context, iop *istiov1alpha1.IstioOperator) (ctrl.Result, error) {
 	log := log.FromContext(ctx)
 
@@ -55,11 +85,43 @@ func (r *IstioOperatorReconciler) reconcileDelete(ctx context.Context, iop *isti
 		}
 		// HelmApp not found, proceed with finalizer removal
 	} else if hApp.GetName() == iop.GetName() && hApp.Labels != nil && hApp.Labels[constants.ManagedLabel] == constants.ManagedLabelValue {
+		// Proxies lose their control plane when istiod is removed, wait until they are moved away
+		if !isForceDelete(iop) && hApp.DeletionTimestamp.IsZero() {
+			usage, err := r.proxiesOfControlPlane(ctx, iop, hApp)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if usage.count > 0 {
+				log.Info("Deletion blocked by proxies using the control plane", "IstioOperator", iop.Name, "pods", usage.count)
+				if iop.Status == nil {
+					iop.Status = &istiov1alpha1.InstallStatus{}
+				}
+				iop.Status.Status = istiov1alpha1.InstallStatus_ACTION_REQUIRED
+				iop.Status.Message = deletionBlockedMessage(iop, usage)
+				if err := r.Status().Update(ctx, iop); err != nil {
+					return ctrl.Result{RequeueAfter: serverFailedAfter}, fmt.Errorf("failed to update iop status: %w", err)
+				}
+				return ctrl.Result{RequeueAfter: failedAfter}, nil
+			}
+		}
+
 		// HelmApp found, attempt to delete it
-		if err := r.Delete(ctx, hApp); err != nil {
+		if err := r.Delete(ctx, hApp); err != nil && !errors.IsNotFound(err) {
 			return ctrl.Result{}, fmt.Errorf("failed to delete HelmApp: %w", err)
 		}
 		log.Info("HelmApp deleted successfully", "HelmApp", hApp.Name)
+
+		// Wait until its releases are uninstalled, the shared base is installed once its base is removed
+		if err := r.Get(ctx, client.ObjectKeyFromObject(hApp), hApp); err == nil {
+			return ctrl.Result{RequeueAfter: reconcileAfter}, nil
+		} else if !errors.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get HelmApp: %w", err)
+		}
+	}
+
+	// The shared base is taken over by another revision, or removed with the last one
+	if err := r.reconcileSharedBase(ctx); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to reconcile the shared base: %w", err)
 	}
 
 	// Remove the finalizer from the IstioOperator
@@ -101,8 +163,30 @@ func (r *IstioOperatorReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	helmApp, err := r.convertIopToHelmApp(ctx, iop)
 	if err != nil {
 		log.Error(err, "Failed to convert IstioOperator to HelmApp")
+		var pErr *unknownProfileError
+		var vErr *invalidVersionError
+		if errors2.As(err, &pErr) || errors2.As(err, &vErr) {
+			// Retrying does not help until the profile is fixed
+			return r.updateErrorStatus(ctx, iop, err)
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The base chart of the revisions is installed by a HelmApp they share, without revision it waits
+	// for the shared one to be removed
+	if err := r.reconcileSharedBase(ctx); err != nil {
+		log.Error(err, "Failed to reconcile the shared base")
+		return ctrl.Result{}, err
+	}
+	sharedBase, err := r.getClusterHelmApp(ctx)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if iop.Spec.GetRevision() != "" || sharedBase != nil {
+		if _, err := splitBase(helmApp); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
 
 	// Create or update the HelmApp
 	if err := r.createOrUpdateHelmApp(ctx, helmApp); err != nil {
@@ -110,57 +194,69 @@ func (r *IstioOperatorReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
-	status := r.calculateOverallPhase(ctx, iop)
-	iop.Status = &istiov1alpha1.InstallStatus{
-		Status: status,
-	}
+	iop.Status = r.calculateStatus(ctx, iop)
 	if err := r.Status().Update(ctx, iop); err != nil {
 		return ctrl.Result{RequeueAfter: serverFailedAfter}, fmt.Errorf("failed to update iop status: %w", err)
 	}
 
-	switch status {
+	switch iop.Status.GetStatus() {
 	case istiov1alpha1.InstallStatus_ERROR:
 		return ctrl.Result{RequeueAfter: failedAfter}, nil
-	case istiov1alpha1.InstallStatus_RECONCILING, istiov1alpha1.InstallStatus_NONE:
+	case istiov1alpha1.InstallStatus_RECONCILING, istiov1alpha1.InstallStatus_UPDATING, istiov1alpha1.InstallStatus_NONE:
 		return ctrl.Result{RequeueAfter: reconcileAfter}, nil
 	default:
 		return ctrl.Result{}, nil
 	}
 }
 
+// updateErrorStatus reports an error of the IstioOperator spec in its status
+func (r *IstioOperatorReconciler) updateErrorStatus(ctx context.Context, iop *istiov1alpha1.IstioOperator, err error) (ctrl.Result, error) {
+	iop.Status = &istiov1alpha1.InstallStatus{
+		Status:  istiov1alpha1.InstallStatus_ERROR,
+		Message: err.Error(),
+	}
+	if err := r.Status().Update(ctx, iop); err != nil {
+		return ctrl.Result{RequeueAfter: serverFailedAfter}, fmt.Errorf("failed to update iop status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: failedAfter}, nil
+}
+
 const (
 	failedAfter       = 90 * time.Second
 	serverFailedAfter = 60 * time.Second
 	reconcileAfter    = 20 * time.Second
 )
 
-func (r *IstioOperatorReconciler) calculateOverallPhase(ctx context.Context, iop *istiov1alpha1.IstioOperator) istiov1alpha1.InstallStatus_Status {
+// calculateStatus builds the status of the IstioOperator from its generated HelmApp
+func (r *IstioOperatorReconciler) calculateStatus(ctx context.Context, iop *istiov1alpha1.IstioOperator) *istiov1alpha1.InstallStatus {
 	if iop == nil {
-		return istiov1alpha1.InstallStatus_NONE
+		return &istiov1alpha1.InstallStatus{Status: istiov1alpha1.InstallStatus_NONE}
 	}
 	helmApp := &operatorv1alpha1.HelmApp{}
 	err := r.Get(ctx, client.ObjectKey{Namespace: iop.GetNamespace(), Name: iop.GetName()}, helmApp)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return istiov1alpha1.InstallStatus_RECONCILING
+			return &istiov1alpha1.InstallStatus{
+				Status:  istiov1alpha1.InstallStatus_RECONCILING,
+				Message: fmt.Sprintf("Waiting for HelmApp %s/%s", iop.GetNamespace(), iop.GetName()),
+			}
+		}
+		return &istiov1alpha1.InstallStatus{
+			Status:  istiov1alpha1.InstallStatus_NONE,
+			Message: fmt.Sprintf("Failed to get HelmApp %s/%s: %v", iop.GetNamespace(), iop.GetName(), err),
 		}
-		return istiov1alpha1.InstallStatus_NONE
-	}
-
-	phase := operatorv1alpha1.Phase_UNKNOWN
-	if helmApp.Status != nil {
-		phase = helmApp.Status.GetPhase()
 	}
-	switch phase {
-	case operatorv1alpha1.Phase_UNKNOWN:
-		return istiov1alpha1.InstallStatus_NONE
-	case operatorv1alpha1.Phase_SUCCEEDED:
-		return istiov1alpha1.InstallStatus_HEALTHY
-	case operatorv1alpha1.Phase_FAILED:
-		return istiov1alpha1.InstallStatus_ERROR
-	default:
-		return istiov1alpha1.InstallStatus_RECONCILING
+	// The revisions report the status of the base they share
+	var sharedBase *operatorv1alpha1.HelmApp
+	if iop.Spec.GetRevision() != "" {
+		if sharedBase, err = r.getClusterHelmApp(ctx); err != nil {
+			return &istiov1alpha1.InstallStatus{
+				Status:  istiov1alpha1.InstallStatus_NONE,
+				Message: fmt.Sprintf("Failed to get HelmApp %s: %v", clusterHelmAppName, err),
+			}
+		}
 	}
+	return installStatusFromHelmApp(helmApp, sharedBase)
 }
 
 func structToMap(in any) map[string]interface{} {
@@ -202,6 +298,15 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal IstioOperator to YAML: %w", err)
 	}
+	iopValues, err := values.MapFromYaml(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse IstioOperator: %w", err)
+	}
+	iopValues, err = applyProfile(iopValues, r.Config.ProfilesDir)
+	if err != nil {
+		return nil, err
+	}
+	data = []byte(iopValues.YAML())
 
 	if _, err := tempFile.Write(data); err != nil {
 		return nil, fmt.Errorf("failed to write to temp file: %w", err)
@@ -216,18 +321,34 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 		return fmt.Sprintf("iop-%s-%s", in.GetName(), p)
 	}
 
-	version := "1.22.8"
-	if tag := in.Spec.GetTag().GetStringValue(); tag != "" {
-		version = tag
+	// Releases of a revision are installed side by side with the ones of other revisions,
+	// the base chart is shared by all of them, see reconcileSharedBase
+	revision := in.Spec.GetRevision()
+
+	istioVersion, err := resolveVersion(in, r.Config.IstioVersion)
+	if err != nil {
+		return nil, err
 	}
+	if istioVersion.compatibilityVersion != "" && !istioVersion.supportsCompatibilityVersion() {
+		log.Info("Ignoring compatibilityVersion, the charts do not support it",
+			"compatibilityVersion", istioVersion.compatibilityVersion, "chartVersion", istioVersion.chart)
+	}
+	version := istioVersion.chart
 
 	components := make([]*operatorv1alpha1.HelmComponent, 0, len(mRes.Components))
+	// Istio component of each release, so the status of the IstioOperator can be reported per component
+	componentNames := map[string]string{}
+	// Settings of each release its chart has no value for, reported in the status of the IstioOperator
+	unsupportedSettings := map[string][]string{}
 	var globalValues *structpb.Struct
-	gwIndex := 0
 	for _, cInfo := range mRes.Components {
 		if globalValues == nil {
 			vals, _ := cInfo.Values.GetPathMap("spec.values")
 			values := structToMap(vals)
+			if values == nil {
+				values = map[string]interface{}{}
+			}
+			istioVersion.applyToValues(values)
 			componentValuesStruct, err := structpb.NewStruct(values)
 			if err != nil {
 				log.Error(err, "Failed to convert component values to structpb.Struct", "values", values)
@@ -238,73 +359,59 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 
 		componentValues := make(map[string]interface{})
 		if isGateway(cInfo) {
+			// Each gateway of the IstioOperator is converted from its own spec into its own release
+			gwSpec := cInfo.ComponentSpec
+			if gwSpec.Enabled != nil && !gwSpec.Enabled.GetValueOrTrue() {
+				log.Info("Skipping disabled gateway", "gateway", gwSpec.Name)
+				continue
+			}
+			gwName := gwSpec.Name
+			if gwName == "" {
+				gwName = defaultGatewayName(cInfo)
+			}
 			gwComp := &operatorv1alpha1.HelmComponent{
-				Name:                   cInfo.ComponentSpec.Name,
+				Name:                   revisionedName(gwName, revision),
 				Chart:                  "gateway",
 				Version:                version,
 				EnableSchemaValidation: true, // Enable schema validation for gateway components
 			}
+			if gwSpec.Namespace != "" && gwSpec.Namespace != in.GetNamespace() {
+				gwComp.Namespace = gwSpec.Namespace
+			}
 
 			labels := map[string]string{}
+			for k, v := range gwSpec.Label {
+				labels[k] = v
+			}
 
-			// Extract gateway configuration
-			componentsKey := fmt.Sprintf("spec.components.%s", cInfo.Component.SpecName)
-			if componentsGateway, ok := cInfo.Values.GetPath(componentsKey); ok {
-				// Convert to GatewayComponentSpec using JSON marshal/unmarshal
-				jsonBytes, err := json.Marshal(componentsGateway)
-				if err != nil {
-					log.Error(err, "Failed to marshal gateway", componentsKey)
-					continue
-				}
-
-				var gws []*apis.GatewayComponentSpec
-				if err := json.Unmarshal(jsonBytes, &gws); err != nil {
-					log.Error(err, "Failed to unmarshal to GatewayComponentSpec")
-					continue
-				}
-
-				if len(gws) > gwIndex {
-					k8sValuesMap := structToMap(gws[gwIndex].Kubernetes)
-					for k, v := range k8sValuesMap {
-						if k == "env" {
-							continue
-						}
-						componentValues[k] = v
-					}
-
-					// env processing
-					envValues := map[string]string{}
-					if gws[gwIndex].Kubernetes != nil {
-						for _, e := range gws[gwIndex].Kubernetes.Env {
-							envValues[e.Name] = e.Value
-						}
-						componentValues["env"] = structToMap(envValues)
-					}
-
-					if gws[gwIndex].Label != nil {
-						labels = gws[gwIndex].Label
-					}
-					gwIndex++
-				}
+			mapped, unsupported := k8sValues(gwSpec, gatewayK8sMappings)
+			componentValues = mapped
+			overlays, unsupportedOverlays := k8sOverlays(gwSpec)
+			gwComp.Overlays = overlays
+			unsupported = append(unsupported, unsupportedOverlays...)
+			if len(unsupported) > 0 {
+				log.Info("Ignoring k8s settings unsupported by the gateway chart", "gateway", gwName, "settings", unsupported)
+				unsupportedSettings[gwComp.Name] = unsupported
 			}
 
 			// spec.values.gateways.istio-ingressgateway.autoscaleEnabled
 			// spec.values.gateways.istio-egressgateway.autoscaleEnabled
-			// Default autoscaling is disabled to ensure it can be explicitly disabled
-			autoscaling := map[string]interface{}{
-				"enabled": false,
+			// Autoscaling is enabled by autoscaleEnabled, or by k8s.hpaSpec when autoscaleEnabled is not set,
+			// and disabled otherwise to ensure it can be explicitly disabled
+			autoscaling, ok := componentValues["autoscaling"].(map[string]interface{})
+			if !ok {
+				autoscaling = map[string]interface{}{}
 			}
 			autoscaleEnabledKey := fmt.Sprintf("spec.values.%s.autoscaleEnabled", cInfo.Component.ToHelmValuesTreeRoot)
 			minReplicasKey := fmt.Sprintf("spec.values.%s.autoscaleMin", cInfo.Component.ToHelmValuesTreeRoot)
-			// Configure autoscaling settings
-			if enabled := cInfo.Values.GetPathBool(autoscaleEnabledKey); enabled {
-				autoscaling["enabled"] = true
-
-				if minReplicas := cInfo.Values.GetPathString(minReplicasKey); minReplicas != "" {
-					autoscaling["minReplicas"] = minReplicas
-				}
+			enabled := gwSpec.Kubernetes != nil && gwSpec.Kubernetes.HpaSpec != nil
+			if v, ok := cInfo.Values.GetPath(autoscaleEnabledKey); ok && v != nil {
+				enabled = cInfo.Values.GetPathBool(autoscaleEnabledKey)
+			}
+			autoscaling["enabled"] = enabled
+			if minReplicas := cInfo.Values.GetPathString(minReplicasKey); enabled && minReplicas != "" {
+				autoscaling["minReplicas"] = minReplicas
 			}
-			// If autoscaleEnabled is explicitly set to false or not set, autoscaling remains disabled (default)
 			componentValues["autoscaling"] = autoscaling
 
 			// compatible iop  gateway template
@@ -321,6 +428,9 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 				labels["istio"] = istioValue
 			}
 			componentValues["labels"] = structToMap(labels)
+			if revision != "" {
+				componentValues["revision"] = revision
+			}
 
 			// Convert values to struct
 			if componentValuesStruct, err := structpb2.NewStruct(componentValues); err != nil {
@@ -328,50 +438,45 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 			} else {
 				gwComp.ComponentValues = componentValuesStruct
 				components = append(components, gwComp)
+				componentNames[gwComp.Name] = string(cInfo.Component.UserFacingName)
 			}
 			continue
 		}
 
-		componentK8SKey := fmt.Sprintf("spec.components.%s.k8s", cInfo.Component.SpecName)
-		if componentsGateway, ok := cInfo.Values.GetPath(componentK8SKey); ok {
-			// Convert to GatewayComponentSpec using JSON marshal/unmarshal
-			jsonBytes, err := json.Marshal(componentsGateway)
-			if err != nil {
-				log.Error(err, "Failed to marshal", componentK8SKey)
-				continue
-			}
-
-			var k8s apis.KubernetesResources
-			if err := json.Unmarshal(jsonBytes, &k8s); err != nil {
-				log.Error(err, "Failed to unmarshal to GatewayComponentSpec")
-				continue
-			}
-
-			k8sValuesMap := structToMap(&k8s)
-			iopC := getComponent(cInfo.Component.SpecName)
-			if iopC.HelmBaseRootKey != "" {
-				baseValues := map[string]interface{}{}
-				for k, v := range k8sValuesMap {
-					baseValues[k] = v
-				}
-				componentValues[iopC.HelmBaseRootKey] = baseValues
-			} else {
-				for k, v := range k8sValuesMap {
-					componentValues[k] = v
-				}
-			}
-		}
-
 		name := cInfo.Component.ReleaseName
 		if name == "" {
 			log.Error(fmt.Errorf("invalid component name"), "Component name is empty")
 			continue
 		}
 
+		releaseName := buildName(name)
+		if cInfo.Component.SpecName == baseSpecName && revision != "" {
+			releaseName = sharedBaseRelease
+		} else if !isClusterComponent(cInfo) && revision != "" {
+			releaseName = revisionedName(releaseName, revision)
+			componentValues["revision"] = revision
+		}
+
+		iopC := getComponent(cInfo.Component.SpecName)
+		mapped, unsupported := k8sValues(cInfo.ComponentSpec, iopC.K8sMappings)
+		overlays, unsupportedOverlays := k8sOverlays(cInfo.ComponentSpec)
+		unsupported = append(unsupported, unsupportedOverlays...)
+		if len(unsupported) > 0 {
+			log.Info("Ignoring k8s settings unsupported by the chart", "component", name, "settings", unsupported)
+			unsupportedSettings[releaseName] = unsupported
+		}
+		if len(mapped) > 0 {
+			if iopC.HelmBaseRootKey != "" {
+				mapped = map[string]interface{}{iopC.HelmBaseRootKey: mapped}
+			}
+			componentValues = tools.MergeMaps(componentValues, mapped)
+		}
+
 		helmComp := &operatorv1alpha1.HelmComponent{
-			Name:    buildName(name),
-			Chart:   name,
-			Version: version,
+			Name:     releaseName,
+			Chart:    name,
+			Version:  version,
+			Overlays: overlays,
 		}
 		if len(componentValues) > 0 {
 			componentValuesStruct, err := structpb2.NewStruct(componentValues)
@@ -382,12 +487,18 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 		}
 
 		components = append(components, helmComp)
+		componentNames[helmComp.Name] = string(cInfo.Component.UserFacingName)
 	}
 
 	if len(components) == 0 {
 		return nil, fmt.Errorf("no valid components found")
 	}
 
+	names, err := json.Marshal(componentNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal component names: %w", err)
+	}
+
 	repo := "https://istio-release.storage.googleapis.com/charts"
 	if v := in.GetAnnotations()[constants.IOPSourceRepoLabel]; v != "" {
 		repo = v
@@ -401,6 +512,9 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 				constants.AllowForceUpgradeLabel: "true",
 				constants.SourceFromIOP:          in.GetName(),
 			},
+			Annotations: map[string]string{
+				constants.IOPComponentsAnnotation: string(names),
+			},
 		},
 		Spec: &operatorv1alpha1.HelmAppSpec{
 			Components:   components,
@@ -412,6 +526,14 @@ func (r *IstioOperatorReconciler) convertIopToHelmApp(ctx context.Context, in *i
 		},
 	}
 
+	if len(unsupportedSettings) > 0 {
+		unsupported, err := json.Marshal(unsupportedSettings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal unsupported settings: %w", err)
+		}
+		happ.Annotations[constants.IOPUnsupportedAnnotation] = string(unsupported)
+	}
+
 	if labels := in.GetLabels(); labels != nil {
 		if v, ok := labels[constants.AllowForceUpgradeLabel]; ok {
 			happ.Labels[constants.AllowForceUpgradeLabel] = v
@@ -445,10 +567,23 @@ func (r *IstioOperatorReconciler) createOrUpdateHelmApp(ctx context.Context, hel
 		managed = true
 	}
 	// HelmApp exists, check if update is needed
+	componentNames := helmApp.GetAnnotations()[constants.IOPComponentsAnnotation]
+	unsupported := helmApp.GetAnnotations()[constants.IOPUnsupportedAnnotation]
 	if managed && (!reflect.DeepEqual(existingHelmApp.Labels, helmApp.Labels) ||
+		existingHelmApp.GetAnnotations()[constants.IOPComponentsAnnotation] != componentNames ||
+		existingHelmApp.GetAnnotations()[constants.IOPUnsupportedAnnotation] != unsupported ||
 		!reflect.DeepEqual(existingHelmApp.Spec, helmApp.Spec)) {
 		log.Info("Updating existing HelmApp", "namespace", helmApp.Namespace, "name", helmApp.Name)
 		existingHelmApp.Labels = helmApp.Labels
+		if existingHelmApp.Annotations == nil {
+			existingHelmApp.Annotations = map[string]string{}
+		}
+		existingHelmApp.Annotations[constants.IOPComponentsAnnotation] = componentNames
+		if unsupported != "" {
+			existingHelmApp.Annotations[constants.IOPUnsupportedAnnotation] = unsupported
+		} else {
+			delete(existingHelmApp.Annotations, constants.IOPUnsupportedAnnotation)
+		}
 		existingHelmApp.Spec = helmApp.Spec
 		if err := r.Update(ctx, existingHelmApp); err != nil {
 			return fmt.Errorf("failed to update HelmApp: %w", err)
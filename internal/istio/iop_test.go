@@ -2,6 +2,7 @@ package istio
 
 import (
 	"context"
+	"reflect"
 	"testing"
 
 	"google.golang.org/protobuf/types/known/structpb"
@@ -10,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
 	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"sigs.k8s.io/yaml"
 )
 
 func TestIstioOperatorReconciler_convertIopToHelmApp_WithSchemaValidation(t *testing.T) {
@@ -131,7 +133,7 @@ func TestIstioOperatorReconciler_convertIopToHelmApp_WithSchemaValidation(t *tes
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a mock reconciler
-			reconciler := &IstioOperatorReconciler{}
+			reconciler := newFakeReconciler()
 
 			// Convert IstioOperator to HelmApp
 			helmApp, err := reconciler.convertIopToHelmApp(context.Background(), tt.iop)
@@ -605,7 +607,7 @@ func TestIstioOperatorReconciler_convertIopToHelmApp_Autoscaling(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a mock reconciler
-			reconciler := &IstioOperatorReconciler{}
+			reconciler := newFakeReconciler()
 
 			// Convert IstioOperator to HelmApp
 			helmApp, err := reconciler.convertIopToHelmApp(context.Background(), tt.iop)
@@ -669,3 +671,83 @@ func TestIstioOperatorReconciler_convertIopToHelmApp_Autoscaling(t *testing.T) {
 		})
 	}
 }
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_MultipleGateways(t *testing.T) {
+	iop := &istiov1alpha1.IstioOperator{}
+	if err := yaml.Unmarshal([]byte(`
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  name: istio
+  namespace: istio-system
+spec:
+  profile: minimal
+  components:
+    ingressGateways:
+    - name: istio-ingressgateway
+      enabled: true
+      k8s:
+        replicaCount: 2
+    - name: internal-ingressgateway
+      enabled: true
+      namespace: internal
+      label:
+        istio: internal-ingressgateway
+      k8s:
+        env:
+        - name: ISTIO_META_ROUTER_MODE
+          value: standard
+    - name: disabled-ingressgateway
+      enabled: false
+    egressGateways:
+    - name: istio-egressgateway
+      enabled: true
+      namespace: egress
+`), iop); err != nil {
+		t.Fatalf("failed to unmarshal IstioOperator: %v", err)
+	}
+
+	helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), iop)
+	if err != nil {
+		t.Fatalf("convertIopToHelmApp() error = %v", err)
+	}
+
+	type gateway struct {
+		namespace string
+		labels    map[string]interface{}
+		replicas  interface{}
+		env       interface{}
+	}
+	expected := map[string]gateway{
+		"istio-ingressgateway": {
+			labels:   map[string]interface{}{"app": "istio-ingressgateway", "istio": "ingressgateway"},
+			replicas: float64(2),
+		},
+		"internal-ingressgateway": {
+			namespace: "internal",
+			labels:    map[string]interface{}{"app": "istio-ingressgateway", "istio": "internal-ingressgateway"},
+			env:       map[string]interface{}{"ISTIO_META_ROUTER_MODE": "standard"},
+		},
+		"istio-egressgateway": {
+			namespace: "egress",
+			labels:    map[string]interface{}{"app": "istio-egressgateway", "istio": "egressgateway"},
+		},
+	}
+
+	got := map[string]gateway{}
+	for _, c := range helmApp.Spec.Components {
+		if c.Chart != "gateway" {
+			continue
+		}
+		values := c.ComponentValues.AsMap()
+		got[c.Name] = gateway{
+			namespace: c.Namespace,
+			labels:    values["labels"].(map[string]interface{}),
+			replicas:  values["replicaCount"],
+			env:       values["env"],
+		}
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("convertIopToHelmApp() gateways = %+v, want %+v", got, expected)
+	}
+}
@@ -0,0 +1,269 @@
+package istio
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"istio.io/istio/operator/pkg/apis"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+)
+
+// k8sMapping maps a field of the k8s settings of a component to the values of its chart, relative to the
+// HelmBaseRootKey of the component. It returns the paths of the parts of the field the chart has no value for,
+// relative to the field.
+type k8sMapping func(k8s *apis.KubernetesResources, values map[string]interface{}) []string
+
+// pilotK8sMappings are the k8s settings of istiod supported by the istiod chart
+var pilotK8sMappings = map[string]k8sMapping{
+	"affinity": setValue("affinity", func(k8s *apis.KubernetesResources) any { return k8s.Affinity }),
+	"env":      mapEnv,
+	"hpaSpec": mapHpaSpec(hpaKeys{
+		minReplicas: "autoscaleMin",
+		maxReplicas: "autoscaleMax",
+		cpu:         "cpu.targetAverageUtilization",
+		memory:      "memory.targetAverageUtilization",
+		behavior:    "autoscaleBehavior",
+	}),
+	"nodeSelector":       setValue("nodeSelector", func(k8s *apis.KubernetesResources) any { return k8s.NodeSelector }),
+	"podAnnotations":     setValue("podAnnotations", func(k8s *apis.KubernetesResources) any { return k8s.PodAnnotations }),
+	"replicaCount":       setValue("replicaCount", func(k8s *apis.KubernetesResources) any { return k8s.ReplicaCount }),
+	"resources":          setValue("resources", func(k8s *apis.KubernetesResources) any { return k8s.Resources }),
+	"serviceAnnotations": setValue("serviceAnnotations", func(k8s *apis.KubernetesResources) any { return k8s.ServiceAnnotations }),
+	"strategy":           mapRollingUpdate("rollingMaxSurge", "rollingMaxUnavailable"),
+	"tolerations":        setValue("tolerations", func(k8s *apis.KubernetesResources) any { return k8s.Tolerations }),
+	"volumeMounts":       setValue("volumeMounts", func(k8s *apis.KubernetesResources) any { return k8s.VolumeMounts }),
+	"volumes":            setValue("volumes", func(k8s *apis.KubernetesResources) any { return k8s.Volumes }),
+}
+
+// cniK8sMappings are the k8s settings of the CNI node agent supported by the cni chart, its DaemonSet only
+// has a max unavailable rolling update setting
+var cniK8sMappings = map[string]k8sMapping{
+	"affinity":        setValue("affinity", func(k8s *apis.KubernetesResources) any { return k8s.Affinity }),
+	"env":             mapEnv,
+	"imagePullPolicy": setValue("pullPolicy", func(k8s *apis.KubernetesResources) any { return k8s.ImagePullPolicy }),
+	"podAnnotations":  setValue("podAnnotations", func(k8s *apis.KubernetesResources) any { return k8s.PodAnnotations }),
+	"resources":       setValue("resources", func(k8s *apis.KubernetesResources) any { return k8s.Resources }),
+	"strategy":        mapRollingUpdate("", "rollingMaxUnavailable"),
+}
+
+// ztunnelK8sMappings are the k8s settings of ztunnel supported by the ztunnel chart
+var ztunnelK8sMappings = map[string]k8sMapping{
+	"affinity":        setValue("affinity", func(k8s *apis.KubernetesResources) any { return k8s.Affinity }),
+	"env":             mapEnv,
+	"imagePullPolicy": setValue("imagePullPolicy", func(k8s *apis.KubernetesResources) any { return k8s.ImagePullPolicy }),
+	"nodeSelector":    setValue("nodeSelector", func(k8s *apis.KubernetesResources) any { return k8s.NodeSelector }),
+	"podAnnotations":  setValue("podAnnotations", func(k8s *apis.KubernetesResources) any { return k8s.PodAnnotations }),
+	"resources":       setValue("resources", func(k8s *apis.KubernetesResources) any { return k8s.Resources }),
+	"volumeMounts":    setValue("volumeMounts", func(k8s *apis.KubernetesResources) any { return k8s.VolumeMounts }),
+	"volumes":         setValue("volumes", func(k8s *apis.KubernetesResources) any { return k8s.Volumes }),
+}
+
+// k8sValues maps the k8s settings of a component to the values of its chart with the mappings of the component.
+// The paths of the settings the chart has no value for are returned as unsupported, e.g. `k8s.service.clusterIP`,
+// they are not passed to the chart.
+func k8sValues(spec apis.GatewayComponentSpec, mappings map[string]k8sMapping) (values map[string]interface{}, unsupported []string) {
+	values = map[string]interface{}{}
+	if spec.Kubernetes == nil {
+		return values, nil
+	}
+
+	// The fields set in the IstioOperator, including the ones unknown to KubernetesResources
+	fields := map[string]bool{}
+	k8sFields, _ := toValue(spec.Kubernetes).(map[string]interface{})
+	for field := range k8sFields {
+		fields[field] = true
+	}
+	if raw, ok := spec.Raw["k8s"].(map[string]interface{}); ok {
+		for field := range raw {
+			fields[field] = true
+		}
+	}
+
+	for field := range fields {
+		// The overlays patch the rendered resources instead, see k8sOverlays
+		if field == "overlays" {
+			continue
+		}
+		mapping, ok := mappings[field]
+		if !ok {
+			unsupported = append(unsupported, "k8s."+field)
+			continue
+		}
+		for _, path := range mapping(spec.Kubernetes, values) {
+			unsupported = append(unsupported, fmt.Sprintf("k8s.%s.%s", field, path))
+		}
+	}
+	sort.Strings(unsupported)
+	return values, unsupported
+}
+
+// k8sOverlays converts the k8s overlays of a component to the overlays of its release, which keep the path
+// syntax of the IstioOperator. The patches whose value cannot be converted are returned as unsupported.
+func k8sOverlays(spec apis.GatewayComponentSpec) (overlays []*operatorv1alpha1.HelmOverlay, unsupported []string) {
+	if spec.Kubernetes == nil {
+		return nil, nil
+	}
+	for i, o := range spec.Kubernetes.Overlays {
+		overlay := &operatorv1alpha1.HelmOverlay{
+			ApiVersion: o.ApiVersion,
+			Kind:       o.Kind,
+			Name:       o.Name,
+		}
+		for j, p := range o.Patches {
+			patch := &operatorv1alpha1.HelmOverlayPatch{Path: p.Path}
+			// An unset value deletes the path
+			if p.Value != nil {
+				value, err := structpb.NewValue(toValue(p.Value))
+				if err != nil {
+					unsupported = append(unsupported, fmt.Sprintf("k8s.overlays[%d].patches[%d]", i, j))
+					continue
+				}
+				patch.Value = value
+			}
+			overlay.Patches = append(overlay.Patches, patch)
+		}
+		overlays = append(overlays, overlay)
+	}
+	return overlays, unsupported
+}
+
+// setValue maps a field to a value of the chart as is
+func setValue(key string, field func(k8s *apis.KubernetesResources) any) k8sMapping {
+	return func(k8s *apis.KubernetesResources, values map[string]interface{}) []string {
+		if v := toValue(field(k8s)); v != nil {
+			setPath(values, key, v)
+		}
+		return nil
+	}
+}
+
+// mapEnv maps the environment variables, the charts only support values
+func mapEnv(k8s *apis.KubernetesResources, values map[string]interface{}) []string {
+	var unsupported []string
+	env := map[string]interface{}{}
+	for _, e := range k8s.Env {
+		if e.ValueFrom != nil {
+			unsupported = append(unsupported, e.Name+".valueFrom")
+			continue
+		}
+		env[e.Name] = e.Value
+	}
+	if len(env) > 0 {
+		values["env"] = env
+	}
+	return unsupported
+}
+
+// hpaKeys are the values of a chart for the autoscaler spec, as dot separated paths
+type hpaKeys struct {
+	minReplicas string
+	maxReplicas string
+	cpu         string
+	memory      string
+	behavior    string
+}
+
+// mapHpaSpec maps the autoscaler spec, the charts support the CPU and memory utilization metrics
+func mapHpaSpec(keys hpaKeys) k8sMapping {
+	return func(k8s *apis.KubernetesResources, values map[string]interface{}) []string {
+		if k8s.HpaSpec == nil {
+			return nil
+		}
+		var unsupported []string
+		if k8s.HpaSpec.MinReplicas != nil {
+			setPath(values, keys.minReplicas, toValue(*k8s.HpaSpec.MinReplicas))
+		}
+		if k8s.HpaSpec.MaxReplicas > 0 {
+			setPath(values, keys.maxReplicas, toValue(k8s.HpaSpec.MaxReplicas))
+		}
+		for i, metric := range k8s.HpaSpec.Metrics {
+			target := utilizationTarget(metric)
+			switch {
+			case target != nil && metric.Resource.Name == corev1.ResourceCPU:
+				setPath(values, keys.cpu, toValue(*target))
+			case target != nil && metric.Resource.Name == corev1.ResourceMemory:
+				setPath(values, keys.memory, toValue(*target))
+			default:
+				unsupported = append(unsupported, fmt.Sprintf("metrics[%d]", i))
+			}
+		}
+		if k8s.HpaSpec.Behavior != nil {
+			setPath(values, keys.behavior, toValue(k8s.HpaSpec.Behavior))
+		}
+		return unsupported
+	}
+}
+
+// utilizationTarget returns the average utilization of a resource metric
+func utilizationTarget(metric autoscalingv2.MetricSpec) *int32 {
+	if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil ||
+		metric.Resource.Target.Type != autoscalingv2.UtilizationMetricType {
+		return nil
+	}
+	return metric.Resource.Target.AverageUtilization
+}
+
+// mapRollingUpdate maps the rolling update of the deployment strategy to the values of a chart, an empty key
+// is a setting the chart has no value for
+func mapRollingUpdate(maxSurgeKey, maxUnavailableKey string) k8sMapping {
+	return func(k8s *apis.KubernetesResources, values map[string]interface{}) []string {
+		strategy, ok := toValue(k8s.Strategy).(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		var unsupported []string
+		for field := range strategy {
+			if field != "rollingUpdate" {
+				unsupported = append(unsupported, field)
+			}
+		}
+		rollingUpdate, _ := strategy["rollingUpdate"].(map[string]interface{})
+		for field, value := range rollingUpdate {
+			key := ""
+			switch field {
+			case "maxSurge":
+				key = maxSurgeKey
+			case "maxUnavailable":
+				key = maxUnavailableKey
+			}
+			if key == "" {
+				unsupported = append(unsupported, "rollingUpdate."+field)
+				continue
+			}
+			setPath(values, key, value)
+		}
+		return unsupported
+	}
+}
+
+// setPath sets a value at a dot separated path, creating the maps on the way
+func setPath(values map[string]interface{}, path string, value interface{}) {
+	keys := strings.Split(path, ".")
+	for _, key := range keys[:len(keys)-1] {
+		next, ok := values[key].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			values[key] = next
+		}
+		values = next
+	}
+	values[keys[len(keys)-1]] = value
+}
+
+// toValue converts a field to the JSON value of the chart values, e.g. quantities to strings
+func toValue(v any) any {
+	var out any
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
@@ -0,0 +1,191 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"sigs.k8s.io/yaml"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of testdata")
+
+// TestIstioOperatorReconciler_convertIopToHelmApp_K8s converts the k8s settings of each component of
+// testdata/k8s/<chart>.yaml and compares the values of its release with testdata/k8s/<chart>.golden.yaml
+func TestIstioOperatorReconciler_convertIopToHelmApp_K8s(t *testing.T) {
+	for _, chart := range []string{"base", "istiod", "cni", "ztunnel"} {
+		t.Run(chart, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "k8s", chart+".yaml"))
+			if err != nil {
+				t.Fatalf("failed to read IstioOperator: %v", err)
+			}
+			iop := &istiov1alpha1.IstioOperator{}
+			if err := yaml.Unmarshal(data, iop); err != nil {
+				t.Fatalf("failed to unmarshal IstioOperator: %v", err)
+			}
+
+			helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), iop)
+			if err != nil {
+				t.Fatalf("convertIopToHelmApp() error = %v", err)
+			}
+			unsupported := map[string][]string{}
+			if v := helmApp.Annotations[constants.IOPUnsupportedAnnotation]; v != "" {
+				if err := json.Unmarshal([]byte(v), &unsupported); err != nil {
+					t.Fatalf("failed to unmarshal unsupported settings: %v", err)
+				}
+			}
+
+			got := map[string]interface{}{}
+			for _, c := range helmApp.Spec.Components {
+				if c.Chart != chart {
+					continue
+				}
+				got["values"] = c.ComponentValues.AsMap()
+				got["unsupported"] = unsupported[c.Name]
+			}
+			if len(got) == 0 {
+				t.Fatalf("convertIopToHelmApp() has no %s release", chart)
+			}
+			out, err := yaml.Marshal(got)
+			if err != nil {
+				t.Fatalf("failed to marshal values: %v", err)
+			}
+
+			golden := filepath.Join("testdata", "k8s", chart+".golden.yaml")
+			if *updateGolden {
+				if err := os.WriteFile(golden, out, 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+			}
+			expected, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+			if string(out) != string(expected) {
+				t.Errorf("convertIopToHelmApp() values =\n%s\nwant\n%s", out, expected)
+			}
+		})
+	}
+}
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_Overlays(t *testing.T) {
+	iop := &istiov1alpha1.IstioOperator{}
+	if err := yaml.Unmarshal([]byte(`
+apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+metadata:
+  name: istio
+  namespace: istio-system
+spec:
+  profile: minimal
+  components:
+    pilot:
+      k8s:
+        overlays:
+        - kind: Deployment
+          name: istiod
+          patches:
+          - path: spec.template.spec.containers.[name:discovery].args.[1]
+    ingressGateways:
+    - name: istio-ingressgateway
+      enabled: true
+      k8s:
+        overlays:
+        - apiVersion: apps/v1
+          kind: Deployment
+          name: istio-ingressgateway
+          patches:
+          - path: spec.template.spec.containers.[name:istio-proxy].ports.[containerPort:8080].hostPort
+            value: 80
+          - path: metadata.labels.team
+            value: mesh
+`), iop); err != nil {
+		t.Fatalf("failed to unmarshal IstioOperator: %v", err)
+	}
+
+	helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), iop)
+	if err != nil {
+		t.Fatalf("convertIopToHelmApp() error = %v", err)
+	}
+
+	expected := map[string][]*operatorv1alpha1.HelmOverlay{
+		"iop-istio-istiod": {{
+			Kind: "Deployment",
+			Name: "istiod",
+			Patches: []*operatorv1alpha1.HelmOverlayPatch{
+				{Path: "spec.template.spec.containers.[name:discovery].args.[1]"},
+			},
+		}},
+		"istio-ingressgateway": {{
+			ApiVersion: "apps/v1",
+			Kind:       "Deployment",
+			Name:       "istio-ingressgateway",
+			Patches: []*operatorv1alpha1.HelmOverlayPatch{
+				{
+					Path:  "spec.template.spec.containers.[name:istio-proxy].ports.[containerPort:8080].hostPort",
+					Value: structpb.NewNumberValue(80),
+				},
+				{Path: "metadata.labels.team", Value: structpb.NewStringValue("mesh")},
+			},
+		}},
+	}
+	for _, c := range helmApp.Spec.Components {
+		want, ok := expected[c.Name]
+		if !ok {
+			continue
+		}
+		delete(expected, c.Name)
+		if len(c.Overlays) != len(want) {
+			t.Fatalf("convertIopToHelmApp() %s overlays = %v, want %v", c.Name, c.Overlays, want)
+		}
+		for i := range want {
+			if !proto.Equal(c.Overlays[i], want[i]) {
+				t.Errorf("convertIopToHelmApp() %s overlay = %v, want %v", c.Name, c.Overlays[i], want[i])
+			}
+		}
+	}
+	for name := range expected {
+		t.Errorf("convertIopToHelmApp() has no %s release", name)
+	}
+	if got := helmApp.Annotations[constants.IOPUnsupportedAnnotation]; got != "" {
+		t.Errorf("convertIopToHelmApp() unsupported = %s, want none", got)
+	}
+}
+
+func TestInstallStatusFromHelmApp_UnmatchedOverlays(t *testing.T) {
+	helmApp := &operatorv1alpha1.HelmApp{
+		ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system"},
+		Spec: &operatorv1alpha1.HelmAppSpec{
+			Components: []*operatorv1alpha1.HelmComponent{
+				{Name: "iop-istio-istiod", Chart: "istiod"},
+				{Name: "istio-ingressgateway", Chart: "gateway"},
+			},
+		},
+		Status: &operatorv1alpha1.HelmAppStatus{
+			Components: []*operatorv1alpha1.HelmComponentStatus{
+				{Name: "iop-istio-istiod", UnmatchedOverlays: []string{"Deployment/istiod-canary"}},
+				{Name: "istio-ingressgateway", UnmatchedOverlays: []string{
+					"Deployment/istio-ingressgateway: spec.template.spec.containers.[name:proxy].image",
+					"Service/istio-ingressgateway: spec.ports.[name:tcp]",
+				}},
+			},
+		},
+	}
+
+	expected := "Components are installed by HelmApp istio-system/istio; unmatched overlays are ignored, " +
+		"iop-istio-istiod: Deployment/istiod-canary; istio-ingressgateway: " +
+		"Deployment/istio-ingressgateway: spec.template.spec.containers.[name:proxy].image, " +
+		"Service/istio-ingressgateway: spec.ports.[name:tcp]"
+	if got := installStatusFromHelmApp(helmApp, nil).Message; got != expected {
+		t.Errorf("installStatusFromHelmApp() message = %q, want %q", got, expected)
+	}
+}
@@ -0,0 +1,90 @@
+package istio
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"istio.io/istio/operator/pkg/values"
+	"pluma.io/pluma-operator/internal/pkg/tools"
+	"pluma.io/pluma-operator/istio/profiles"
+)
+
+const defaultProfile = "default"
+
+// unknownProfileError is returned for a profile found neither in the profiles directory nor in the embedded profiles
+type unknownProfileError struct {
+	profile string
+}
+
+func (e *unknownProfileError) Error() string {
+	return fmt.Sprintf("unknown profile %q", e.profile)
+}
+
+// applyProfile returns the IstioOperator overlaid on its profile. As in istioctl every profile is applied
+// on the default profile. The hub and tag of the profiles are dropped so that the IstioOperator or
+// the Istio release decide of the images. The maps are merged as the values of the HelmApps, the lists
+// of the IstioOperator replace those of the profile.
+func applyProfile(iop values.Map, profilesDir string) (values.Map, error) {
+	name := iop.GetPathString("spec.profile")
+	if name == "" {
+		name = defaultProfile
+	}
+
+	merged, err := readProfile(profilesDir, defaultProfile)
+	if err != nil {
+		return nil, err
+	}
+	if name != defaultProfile {
+		profile, err := readProfile(profilesDir, name)
+		if err != nil {
+			return nil, err
+		}
+		merged = values.Map(tools.MergeMaps(merged, profile))
+	}
+	if spec, ok := merged.GetPathMap("spec"); ok {
+		delete(spec, "hub")
+		delete(spec, "tag")
+	}
+	delete(merged, "metadata")
+
+	merged = values.Map(tools.MergeMaps(merged, iop))
+	// The profile is applied, do not let Istio apply its own copy
+	if spec, ok := merged.GetPathMap("spec"); ok {
+		delete(spec, "profile")
+	}
+	return merged, nil
+}
+
+// readProfile reads `<name>.yaml` from the profiles directory, or from the embedded profiles when the directory lacks it
+func readProfile(profilesDir, name string) (values.Map, error) {
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		// Profiles are names, files of the operator are not readable from an IstioOperator
+		return nil, &unknownProfileError{profile: name}
+	}
+	fileName := name + ".yaml"
+
+	var data []byte
+	var err error
+	if profilesDir != "" {
+		data, err = os.ReadFile(filepath.Join(profilesDir, fileName))
+	}
+	if profilesDir == "" || errors.Is(err, fs.ErrNotExist) {
+		data, err = fs.ReadFile(profiles.FS, fileName)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, &unknownProfileError{profile: name}
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profile %q: %w", name, err)
+	}
+
+	profile, err := values.MapFromYaml(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse profile %q: %w", name, err)
+	}
+	return profile, nil
+}
@@ -0,0 +1,172 @@
+package istio
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"istio.io/istio/operator/pkg/values"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	"pluma.io/pluma-operator/config"
+)
+
+func TestApplyProfile(t *testing.T) {
+	profilesDir := t.TempDir()
+	custom := `apiVersion: install.istio.io/v1alpha1
+kind: IstioOperator
+spec:
+  hub: registry.example.com/istio
+  components:
+    egressGateways:
+    - name: istio-egressgateway
+      enabled: true
+  values:
+    pilot:
+      replicaCount: 3
+`
+	if err := os.WriteFile(filepath.Join(profilesDir, "custom.yaml"), []byte(custom), 0o644); err != nil {
+		t.Fatalf("failed to write profile: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		profilesDir string
+		iop         string
+		expected    map[string]interface{}
+		unknown     bool
+	}{
+		{
+			name: "default profile",
+			iop:  `{"spec": {}}`,
+			expected: map[string]interface{}{
+				"spec.components.base.enabled":                true,
+				"spec.values.global.istioNamespace":           "istio-system",
+				"spec.components.ingressGateways.[0].enabled": true,
+			},
+		},
+		{
+			name: "embedded profile",
+			iop:  `{"spec": {"profile": "demo", "values": {"global": {"istioNamespace": "mesh"}}}}`,
+			expected: map[string]interface{}{
+				"spec.values.profile":                        "demo",
+				"spec.values.global.istioNamespace":          "mesh",
+				"spec.components.egressGateways.[0].enabled": true,
+			},
+		},
+		{
+			name:        "profile of the profiles directory",
+			profilesDir: profilesDir,
+			iop:         `{"spec": {"profile": "custom", "values": {"pilot": {"cpu": {"targetAverageUtilization": 60}}}}}`,
+			expected: map[string]interface{}{
+				"spec.values.pilot.replicaCount":                 3.0,
+				"spec.values.pilot.cpu.targetAverageUtilization": 60.0,
+				"spec.components.egressGateways.[0].enabled":     true,
+				"spec.values.global.istioNamespace":              "istio-system",
+			},
+		},
+		{
+			name:        "embedded fallback for the profiles directory",
+			profilesDir: profilesDir,
+			iop:         `{"spec": {"profile": "minimal"}}`,
+			expected: map[string]interface{}{
+				"spec.components.ingressGateways.[0].enabled": false,
+			},
+		},
+		{
+			name: "lists of the IstioOperator replace the lists of the profile",
+			iop:  `{"spec": {"components": {"ingressGateways": [{"name": "custom-gateway", "enabled": true}]}}}`,
+			expected: map[string]interface{}{
+				"spec.components.ingressGateways.[0].name":    "custom-gateway",
+				"spec.components.ingressGateways.[1].enabled": nil,
+			},
+		},
+		{
+			name:    "unknown profile",
+			iop:     `{"spec": {"profile": "unknown"}}`,
+			unknown: true,
+		},
+		{
+			name:    "paths are not profiles",
+			iop:     `{"spec": {"profile": "../profiles/default"}}`,
+			unknown: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iop, err := values.MapFromYaml([]byte(tt.iop))
+			if err != nil {
+				t.Fatalf("failed to parse iop: %v", err)
+			}
+			got, err := applyProfile(iop, tt.profilesDir)
+			var pErr *unknownProfileError
+			if tt.unknown {
+				if !errors.As(err, &pErr) {
+					t.Fatalf("applyProfile() error = %v, want an unknown profile error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("applyProfile() error = %v", err)
+			}
+
+			for path, expected := range tt.expected {
+				if v, _ := got.GetPath(path); v != expected {
+					t.Errorf("applyProfile() %s = %v, want %v", path, v, expected)
+				}
+			}
+			for _, path := range []string{"spec.profile", "spec.hub", "spec.tag"} {
+				if v, ok := got.GetPath(path); ok {
+					t.Errorf("applyProfile() %s = %v, want it unset", path, v)
+				}
+			}
+		})
+	}
+}
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_Profile(t *testing.T) {
+	reconciler := newFakeReconciler()
+	reconciler.Config = config.Config{ProfilesDir: "../../istio/profiles"}
+
+	tests := []struct {
+		name     string
+		profile  string
+		expected []string
+	}{
+		{name: "default", expected: []string{"base", "istiod", "gateway"}},
+		{name: "minimal", profile: "minimal", expected: []string{"base", "istiod"}},
+		{name: "demo", profile: "demo", expected: []string{"base", "istiod", "gateway", "gateway"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helmApp, err := reconciler.convertIopToHelmApp(context.Background(),
+				newTestIop("test-iop", nil, &istiov1alpha1.IstioOperatorSpec{Profile: tt.profile}))
+			if err != nil {
+				t.Fatalf("convertIopToHelmApp() error = %v", err)
+			}
+			charts := make([]string, 0, len(helmApp.Spec.Components))
+			for _, c := range helmApp.Spec.Components {
+				charts = append(charts, c.Chart)
+			}
+			if len(charts) != len(tt.expected) {
+				t.Fatalf("convertIopToHelmApp() charts = %v, want %v", charts, tt.expected)
+			}
+			for i := range charts {
+				if charts[i] != tt.expected[i] {
+					t.Errorf("convertIopToHelmApp() charts = %v, want %v", charts, tt.expected)
+					break
+				}
+			}
+		})
+	}
+
+	_, err := reconciler.convertIopToHelmApp(context.Background(),
+		newTestIop("test-iop", nil, &istiov1alpha1.IstioOperatorSpec{Profile: "unknown"}))
+	var pErr *unknownProfileError
+	if !errors.As(err, &pErr) {
+		t.Errorf("convertIopToHelmApp() error = %v, want an unknown profile error", err)
+	}
+}
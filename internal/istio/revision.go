@@ -0,0 +1,259 @@
+package istio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Masterminds/semver/v3"
+	"istio.io/istio/operator/pkg/render"
+	"k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterHelmAppName is the HelmApp of the base chart shared by the IstioOperators with a revision
+const clusterHelmAppName = "iop-cluster"
+
+// sharedBaseRelease is the release of the shared base chart, it does not depend on the IstioOperator
+// installing it so that it is kept when another one takes it over
+const sharedBaseRelease = "istio-base"
+
+// clusterSpecNames are the components installed once for every IstioOperator, whatever their revision
+var clusterSpecNames = map[string]bool{
+	baseSpecName:    true,
+	cniSpecName:     true,
+	ztunnelSpecName: true,
+}
+
+func isClusterComponent(c render.ComponentMigration) bool {
+	return clusterSpecNames[c.Component.SpecName]
+}
+
+// isBaseComponent reports whether the release of a HelmApp installs the base chart
+func isBaseComponent(c *operatorv1alpha1.HelmComponent) bool {
+	return c.GetChart() == getComponent(baseSpecName).HelmChartName
+}
+
+// revisionedName suffixes the release name with the revision, so that the control planes of
+// several revisions are installed side by side
+func revisionedName(name, revision string) string {
+	if revision == "" {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", name, revision)
+}
+
+// splitBase moves the base chart out of the HelmApp of an IstioOperator into the shared HelmApp, nil when
+// the IstioOperator does not install it. The annotations of the release follow it.
+func splitBase(helmApp *operatorv1alpha1.HelmApp) (*operatorv1alpha1.HelmApp, error) {
+	names := iopComponentNames(helmApp)
+	unsupported := map[string][]string{}
+	if v := helmApp.GetAnnotations()[constants.IOPUnsupportedAnnotation]; v != "" {
+		if err := json.Unmarshal([]byte(v), &unsupported); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal unsupported settings: %w", err)
+		}
+	}
+
+	own := make([]*operatorv1alpha1.HelmComponent, 0, len(helmApp.Spec.GetComponents()))
+	var shared []*operatorv1alpha1.HelmComponent
+	ownNames, sharedNames := map[string]string{}, map[string]string{}
+	ownUnsupported, sharedUnsupported := map[string][]string{}, map[string][]string{}
+	for _, c := range helmApp.Spec.GetComponents() {
+		components, componentNames, componentUnsupported := &own, ownNames, ownUnsupported
+		if isBaseComponent(c) {
+			components, componentNames, componentUnsupported = &shared, sharedNames, sharedUnsupported
+		}
+		*components = append(*components, c)
+		componentNames[c.GetName()] = names[c.GetName()]
+		if settings, ok := unsupported[c.GetName()]; ok {
+			componentUnsupported[c.GetName()] = settings
+		}
+	}
+	if len(shared) == 0 {
+		return nil, nil
+	}
+
+	clusterApp := &operatorv1alpha1.HelmApp{
+		ObjectMeta: v1.ObjectMeta{
+			Name:        clusterHelmAppName,
+			Namespace:   helmApp.GetNamespace(),
+			Labels:      map[string]string{constants.IOPClusterComponentsLabel: "true"},
+			Annotations: map[string]string{},
+		},
+		Spec: &operatorv1alpha1.HelmAppSpec{
+			Components:   shared,
+			GlobalValues: helmApp.Spec.GetGlobalValues(),
+			Repo:         helmApp.Spec.GetRepo(),
+		},
+	}
+	for k, v := range helmApp.GetLabels() {
+		if k != constants.SourceFromIOP {
+			clusterApp.Labels[k] = v
+		}
+	}
+	helmApp.Spec.Components = own
+	if err := setIopAnnotations(helmApp, ownNames, ownUnsupported); err != nil {
+		return nil, err
+	}
+	if err := setIopAnnotations(clusterApp, sharedNames, sharedUnsupported); err != nil {
+		return nil, err
+	}
+	return clusterApp, nil
+}
+
+// setIopAnnotations writes the Istio component and the unsupported settings of the releases of a HelmApp
+func setIopAnnotations(helmApp *operatorv1alpha1.HelmApp, names map[string]string, unsupported map[string][]string) error {
+	if helmApp.Annotations == nil {
+		helmApp.Annotations = map[string]string{}
+	}
+	data, err := json.Marshal(names)
+	if err != nil {
+		return fmt.Errorf("failed to marshal component names: %w", err)
+	}
+	helmApp.Annotations[constants.IOPComponentsAnnotation] = string(data)
+	delete(helmApp.Annotations, constants.IOPUnsupportedAnnotation)
+	if len(unsupported) > 0 {
+		data, err := json.Marshal(unsupported)
+		if err != nil {
+			return fmt.Errorf("failed to marshal unsupported settings: %w", err)
+		}
+		helmApp.Annotations[constants.IOPUnsupportedAnnotation] = string(data)
+	}
+	return nil
+}
+
+// liveIops returns the IstioOperators which are not being deleted
+func (r *IstioOperatorReconciler) liveIops(ctx context.Context) ([]*istiov1alpha1.IstioOperator, error) {
+	iops := &istiov1alpha1.IstioOperatorList{}
+	if err := r.List(ctx, iops); err != nil {
+		return nil, fmt.Errorf("failed to list IstioOperators: %w", err)
+	}
+	var live []*istiov1alpha1.IstioOperator
+	for _, iop := range iops.Items {
+		if iop.DeletionTimestamp.IsZero() {
+			live = append(live, iop)
+		}
+	}
+	return live, nil
+}
+
+// sharedBaseOwner returns the IstioOperator whose base chart is shared by the revisions, nil when an
+// IstioOperator without revision installs it in its own HelmApp or when there is none. The owner has the
+// highest chart version, so that a canary revision runs with its own CRDs, the oldest one among equals.
+func (r *IstioOperatorReconciler) sharedBaseOwner(iops []*istiov1alpha1.IstioOperator) *istiov1alpha1.IstioOperator {
+	var owner *istiov1alpha1.IstioOperator
+	var ownerVersion *semver.Version
+	for _, iop := range iops {
+		if iop.Spec.GetRevision() == "" {
+			return nil
+		}
+		// The IstioOperators with an invalid version report it in their status
+		v, err := resolveVersion(iop, r.Config.IstioVersion)
+		if err != nil {
+			continue
+		}
+		version, err := semver.NewVersion(v.chart)
+		if err != nil {
+			continue
+		}
+		if owner == nil || version.GreaterThan(ownerVersion) || (version.Equal(ownerVersion) && isOlder(iop, owner)) {
+			owner, ownerVersion = iop, version
+		}
+	}
+	return owner
+}
+
+// isOlder orders the IstioOperators by creation, then by namespace and name
+func isOlder(a, b *istiov1alpha1.IstioOperator) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return client.ObjectKeyFromObject(a).String() < client.ObjectKeyFromObject(b).String()
+}
+
+// getClusterHelmApp returns the HelmApp of the shared base, nil when it does not exist. It stays in the
+// namespace of the IstioOperator which created it.
+func (r *IstioOperatorReconciler) getClusterHelmApp(ctx context.Context) (*operatorv1alpha1.HelmApp, error) {
+	helmApps := &operatorv1alpha1.HelmAppList{}
+	if err := r.List(ctx, helmApps, client.MatchingLabels{constants.IOPClusterComponentsLabel: "true"}); err != nil {
+		return nil, fmt.Errorf("failed to list HelmApps: %w", err)
+	}
+	for _, helmApp := range helmApps.Items {
+		if helmApp.Name == clusterHelmAppName {
+			return helmApp, nil
+		}
+	}
+	return nil, nil
+}
+
+// baseOfIopHelmApps reports whether the HelmApp of an IstioOperator still installs base, it is uninstalled
+// before the shared base is installed so that both releases never own the same resources
+func (r *IstioOperatorReconciler) baseOfIopHelmApps(ctx context.Context) (bool, error) {
+	helmApps := &operatorv1alpha1.HelmAppList{}
+	if err := r.List(ctx, helmApps, client.HasLabels{constants.SourceFromIOP}); err != nil {
+		return false, fmt.Errorf("failed to list HelmApps: %w", err)
+	}
+	for _, helmApp := range helmApps.Items {
+		if helmApp.GetLabels()[constants.ManagedLabel] != constants.ManagedLabelValue {
+			continue
+		}
+		for _, c := range helmApp.Spec.GetComponents() {
+			if isBaseComponent(c) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// reconcileSharedBase installs the base chart of the owner of the shared base, see sharedBaseOwner, and
+// removes it when there is no owner. It is installed once the HelmApps of the IstioOperators no longer do.
+func (r *IstioOperatorReconciler) reconcileSharedBase(ctx context.Context) error {
+	live, err := r.liveIops(ctx)
+	if err != nil {
+		return err
+	}
+	existing, err := r.getClusterHelmApp(ctx)
+	if err != nil {
+		return err
+	}
+	owner := r.sharedBaseOwner(live)
+	if owner == nil {
+		if existing != nil {
+			return r.deleteClusterHelmApp(ctx, existing)
+		}
+		return nil
+	}
+
+	helmApp, err := r.convertIopToHelmApp(ctx, owner)
+	if err != nil {
+		return fmt.Errorf("failed to convert IstioOperator %s/%s: %w", owner.Namespace, owner.Name, err)
+	}
+	clusterApp, err := splitBase(helmApp)
+	if err != nil {
+		return err
+	}
+	if clusterApp == nil {
+		if existing != nil {
+			return r.deleteClusterHelmApp(ctx, existing)
+		}
+		return nil
+	}
+	if existing != nil {
+		clusterApp.Namespace = existing.Namespace
+	} else if installed, err := r.baseOfIopHelmApps(ctx); err != nil || installed {
+		return err
+	}
+	return r.createOrUpdateHelmApp(ctx, clusterApp)
+}
+
+func (r *IstioOperatorReconciler) deleteClusterHelmApp(ctx context.Context, helmApp *operatorv1alpha1.HelmApp) error {
+	if err := r.Delete(ctx, helmApp); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete HelmApp: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package istio
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_Revision(t *testing.T) {
+	tests := []struct {
+		name string
+		iop  *istiov1alpha1.IstioOperator
+		// expected maps the release names to their revision value
+		expected map[string]string
+	}{
+		{
+			name: "without revision",
+			iop:  newTestIop("stable", nil, &istiov1alpha1.IstioOperatorSpec{}),
+			expected: map[string]string{
+				"iop-stable-base":      "",
+				"iop-stable-istiod":    "",
+				"istio-ingressgateway": "",
+			},
+		},
+		{
+			name: "the base of the revisions is shared",
+			iop:  newTestIop("canary", nil, &istiov1alpha1.IstioOperatorSpec{Revision: "1-25"}),
+			expected: map[string]string{
+				"istio-base":                "",
+				"iop-canary-istiod-1-25":    "1-25",
+				"istio-ingressgateway-1-25": "1-25",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), tt.iop)
+			if err != nil {
+				t.Fatalf("convertIopToHelmApp() error = %v", err)
+			}
+
+			got := map[string]string{}
+			for _, c := range helmApp.Spec.Components {
+				got[c.Name], _ = c.GetComponentValues().AsMap()["revision"].(string)
+			}
+			if len(got) != len(tt.expected) {
+				t.Fatalf("convertIopToHelmApp() components = %v, want %v", got, tt.expected)
+			}
+			for name, revision := range tt.expected {
+				if r, ok := got[name]; !ok || r != revision {
+					t.Errorf("convertIopToHelmApp() component %s revision = %q, want %q (components %v)", name, r, revision, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitBase(t *testing.T) {
+	helmApp := &operatorv1alpha1.HelmApp{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "canary",
+			Namespace: "istio-system",
+			Labels: map[string]string{
+				constants.ManagedLabel:  constants.ManagedLabelValue,
+				constants.SourceFromIOP: "canary",
+			},
+			Annotations: map[string]string{
+				constants.IOPComponentsAnnotation:  `{"iop-canary-cni":"Cni","iop-canary-istiod-1-25":"Pilot","istio-base":"Base"}`,
+				constants.IOPUnsupportedAnnotation: `{"iop-canary-cni":["k8s.service.clusterIP"]}`,
+			},
+		},
+		Spec: &operatorv1alpha1.HelmAppSpec{
+			Components: []*operatorv1alpha1.HelmComponent{
+				{Name: "istio-base", Chart: "base"},
+				{Name: "iop-canary-istiod-1-25", Chart: "istiod"},
+				{Name: "iop-canary-cni", Chart: "cni"},
+			},
+		},
+	}
+
+	clusterApp, err := splitBase(helmApp)
+	if err != nil {
+		t.Fatalf("splitBase() error = %v", err)
+	}
+	if clusterApp.Name != clusterHelmAppName || clusterApp.Namespace != "istio-system" {
+		t.Errorf("splitBase() HelmApp = %s/%s, want istio-system/%s", clusterApp.Namespace, clusterApp.Name, clusterHelmAppName)
+	}
+	if _, ok := clusterApp.Labels[constants.SourceFromIOP]; ok || clusterApp.Labels[constants.IOPClusterComponentsLabel] != "true" {
+		t.Errorf("splitBase() labels = %v", clusterApp.Labels)
+	}
+
+	expected := map[*operatorv1alpha1.HelmApp]map[string]string{
+		helmApp: {
+			"components":  "iop-canary-istiod-1-25,iop-canary-cni",
+			"names":       `{"iop-canary-cni":"Cni","iop-canary-istiod-1-25":"Pilot"}`,
+			"unsupported": `{"iop-canary-cni":["k8s.service.clusterIP"]}`,
+		},
+		clusterApp: {
+			"components":  "istio-base",
+			"names":       `{"istio-base":"Base"}`,
+			"unsupported": "",
+		},
+	}
+	for app, want := range expected {
+		var components []string
+		for _, c := range app.Spec.Components {
+			components = append(components, c.Name)
+		}
+		got := map[string]string{
+			"components":  strings.Join(components, ","),
+			"names":       app.Annotations[constants.IOPComponentsAnnotation],
+			"unsupported": app.Annotations[constants.IOPUnsupportedAnnotation],
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("splitBase() HelmApp %s = %v, want %v", app.Name, got, want)
+		}
+	}
+
+	withoutBase := &operatorv1alpha1.HelmApp{Spec: &operatorv1alpha1.HelmAppSpec{
+		Components: []*operatorv1alpha1.HelmComponent{{Name: "iop-istio-istiod", Chart: "istiod"}},
+	}}
+	if clusterApp, err := splitBase(withoutBase); err != nil || clusterApp != nil {
+		t.Errorf("splitBase() = %v, %v, want nil", clusterApp, err)
+	}
+}
+
+// TestIstioOperatorReconciler_SharedBase installs a stable and a canary control plane before either of their
+// HelmApps exists, then deletes them in turn
+func TestIstioOperatorReconciler_SharedBase(t *testing.T) {
+	created := metav1.Now()
+	newIop := func(name, revision, tag string, age time.Duration) *istiov1alpha1.IstioOperator {
+		iop := newTestIop(name, nil, &istiov1alpha1.IstioOperatorSpec{
+			Profile: "minimal", Revision: revision, Tag: structpb.NewStringValue(tag),
+		})
+		iop.CreationTimestamp = metav1.NewTime(created.Add(-age))
+		return iop
+	}
+
+	tests := []struct {
+		name   string
+		stable *istiov1alpha1.IstioOperator
+		canary *istiov1alpha1.IstioOperator
+		// expected are the base releases, by HelmApp, after the reconciliation of both, then the deletion of
+		// the stable one and the deletion of the canary one
+		expected []map[string]string
+	}{
+		{
+			name:   "stable without revision",
+			stable: newIop("stable", "", "1.23.4", time.Hour),
+			canary: newIop("canary", "1-24", "1.24.2", 0),
+			expected: []map[string]string{
+				{"stable": "iop-stable-base@1.23.4"},
+				{clusterHelmAppName: "istio-base@1.24.2"},
+				{},
+			},
+		},
+		{
+			name:   "both revisioned",
+			stable: newIop("stable", "1-23", "1.23.4", time.Hour),
+			canary: newIop("canary", "1-24", "1.24.2", 0),
+			expected: []map[string]string{
+				{clusterHelmAppName: "istio-base@1.24.2"},
+				{clusterHelmAppName: "istio-base@1.24.2"},
+				{},
+			},
+		},
+		{
+			name:   "rollback to an older revision",
+			stable: newIop("stable", "1-24", "1.24.2", time.Hour),
+			canary: newIop("canary", "1-23", "1.23.4", 0),
+			expected: []map[string]string{
+				{clusterHelmAppName: "istio-base@1.24.2"},
+				{clusterHelmAppName: "istio-base@1.23.4"},
+				{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeReconciler(tt.stable, tt.canary)
+			ctx := context.Background()
+
+			reconcileIop := func(iop *istiov1alpha1.IstioOperator) {
+				t.Helper()
+				if _, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(iop)}); err != nil {
+					t.Fatalf("Reconcile(%s) error = %v", iop.Name, err)
+				}
+			}
+			// deleteIop deletes an IstioOperator like the API server, setting its deletion timestamp first
+			deleteIop := func(iop *istiov1alpha1.IstioOperator) {
+				t.Helper()
+				if err := r.Get(ctx, client.ObjectKeyFromObject(iop), iop); err != nil {
+					t.Fatalf("failed to get %s: %v", iop.Name, err)
+				}
+				if err := r.Delete(ctx, iop); err != nil {
+					t.Fatalf("failed to delete %s: %v", iop.Name, err)
+				}
+				reconcileIop(iop)
+			}
+			expectBase := func(step int) {
+				t.Helper()
+				helmApps := &operatorv1alpha1.HelmAppList{}
+				if err := r.List(ctx, helmApps); err != nil {
+					t.Fatalf("failed to list HelmApps: %v", err)
+				}
+				got := map[string]string{}
+				for _, helmApp := range helmApps.Items {
+					for _, c := range helmApp.Spec.Components {
+						if isBaseComponent(c) {
+							got[helmApp.Name] = c.Name + "@" + c.Version
+						}
+					}
+				}
+				if !reflect.DeepEqual(got, tt.expected[step]) {
+					t.Errorf("step %d: base releases = %v, want %v", step, got, tt.expected[step])
+				}
+			}
+
+			reconcileIop(tt.canary)
+			reconcileIop(tt.stable)
+			expectBase(0)
+			deleteIop(tt.stable)
+			expectBase(1)
+			deleteIop(tt.canary)
+			expectBase(2)
+		})
+	}
+}
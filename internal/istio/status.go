@@ -0,0 +1,266 @@
+package istio
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	helmrelease "helm.sh/helm/v3/pkg/release"
+	"istio.io/istio/operator/pkg/component"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+)
+
+// overallStatus maps the phase of the HelmApp to the status of the IstioOperator
+func overallStatus(helmApp *operatorv1alpha1.HelmApp) istiov1alpha1.InstallStatus_Status {
+	phase := operatorv1alpha1.Phase_UNKNOWN
+	if helmApp.Status != nil {
+		phase = helmApp.Status.GetPhase()
+	}
+	switch phase {
+	case operatorv1alpha1.Phase_UNKNOWN:
+		return istiov1alpha1.InstallStatus_NONE
+	case operatorv1alpha1.Phase_SUCCEEDED:
+		return istiov1alpha1.InstallStatus_HEALTHY
+	case operatorv1alpha1.Phase_FAILED:
+		return istiov1alpha1.InstallStatus_ERROR
+	default:
+		return istiov1alpha1.InstallStatus_RECONCILING
+	}
+}
+
+// releaseStatus maps the status of a Helm release to the status of an Istio component. A release whose
+// chart version differs from the desired version of the HelmApp is UPDATING until it is upgraded.
+func releaseStatus(cs *operatorv1alpha1.HelmComponentStatus, desiredVersion string) istiov1alpha1.InstallStatus_Status {
+	if cs == nil {
+		return istiov1alpha1.InstallStatus_NONE
+	}
+	switch helmrelease.Status(cs.GetStatus()) {
+	case "":
+		return istiov1alpha1.InstallStatus_NONE
+	case helmrelease.StatusFailed:
+		return istiov1alpha1.InstallStatus_ERROR
+	}
+	if isVersionChanged(cs.GetChartVersion(), desiredVersion) {
+		return istiov1alpha1.InstallStatus_UPDATING
+	}
+	switch helmrelease.Status(cs.GetStatus()) {
+	case helmrelease.StatusDeployed, helmrelease.StatusSuperseded:
+		return istiov1alpha1.InstallStatus_HEALTHY
+	default:
+		return istiov1alpha1.InstallStatus_RECONCILING
+	}
+}
+
+// isVersionChanged reports whether a deployed chart version differs from the desired one, an unknown
+// version is not a change
+func isVersionChanged(deployed, desired string) bool {
+	if deployed == "" || desired == "" {
+		return false
+	}
+	return strings.TrimPrefix(deployed, "v") != strings.TrimPrefix(desired, "v")
+}
+
+// aggregateStatus combines the statuses of several components following the precedence of InstallStatus:
+// ERROR, then ACTION_REQUIRED, then UPDATING, then RECONCILING. Components are NONE or HEALTHY when they
+// all are, a mix of both is RECONCILING since the remaining components are being installed.
+func aggregateStatus(statuses ...istiov1alpha1.InstallStatus_Status) istiov1alpha1.InstallStatus_Status {
+	counts := map[istiov1alpha1.InstallStatus_Status]int{}
+	for _, status := range statuses {
+		counts[status]++
+	}
+	switch {
+	case len(statuses) == 0:
+		return istiov1alpha1.InstallStatus_NONE
+	case counts[istiov1alpha1.InstallStatus_ERROR] > 0:
+		return istiov1alpha1.InstallStatus_ERROR
+	case counts[istiov1alpha1.InstallStatus_ACTION_REQUIRED] > 0:
+		return istiov1alpha1.InstallStatus_ACTION_REQUIRED
+	case counts[istiov1alpha1.InstallStatus_UPDATING] > 0:
+		return istiov1alpha1.InstallStatus_UPDATING
+	case counts[istiov1alpha1.InstallStatus_RECONCILING] > 0:
+		return istiov1alpha1.InstallStatus_RECONCILING
+	case counts[istiov1alpha1.InstallStatus_NONE] == len(statuses):
+		return istiov1alpha1.InstallStatus_NONE
+	case counts[istiov1alpha1.InstallStatus_HEALTHY] == len(statuses):
+		return istiov1alpha1.InstallStatus_HEALTHY
+	default:
+		return istiov1alpha1.InstallStatus_RECONCILING
+	}
+}
+
+// iopComponentNames returns the Istio component of each release of the HelmApp, e.g. `Pilot`. It is read
+// from the annotation written when the HelmApp is generated, HelmApps generated before it fall back to the chart.
+func iopComponentNames(helmApp *operatorv1alpha1.HelmApp) map[string]string {
+	names := map[string]string{}
+	if v := helmApp.GetAnnotations()[constants.IOPComponentsAnnotation]; v != "" {
+		_ = json.Unmarshal([]byte(v), &names)
+	}
+	for _, c := range helmApp.Spec.GetComponents() {
+		if _, ok := names[c.GetName()]; ok {
+			continue
+		}
+		for _, comp := range component.AllComponents {
+			if getComponent(comp.SpecName).HelmChartName == c.GetChart() {
+				names[c.GetName()] = string(comp.UserFacingName)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// installStatusFromHelmApp builds the status of the IstioOperator from its generated HelmApp and the HelmApp of the
+// shared base, nil when it does not use it. The releases of an Istio component, e.g. several ingress gateways, are
+// reported together under its name, their statuses and the ones of the components are aggregated, see
+// aggregateStatus. The message names the first failing release and the HelmApp to look at, followed by the ignored
+// settings and the unmatched overlays of the IstioOperator.
+func installStatusFromHelmApp(helmApp, sharedBase *operatorv1alpha1.HelmApp) *istiov1alpha1.InstallStatus {
+	helmApps := []*operatorv1alpha1.HelmApp{helmApp}
+	if sharedBase != nil {
+		helmApps = append(helmApps, sharedBase)
+	}
+	keys := make([]string, 0, len(helmApps))
+	for _, app := range helmApps {
+		keys = append(keys, app.GetNamespace()+"/"+app.GetName())
+	}
+	status := &istiov1alpha1.InstallStatus{
+		Message: "Components are installed by HelmApp " + keys[0],
+	}
+	if len(helmApps) > 1 {
+		status.Message = "Components are installed by HelmApps " + strings.Join(keys, " and ")
+	}
+
+	var messages []string
+	var statuses []istiov1alpha1.InstallStatus_Status
+	releases := 0
+	failed := false
+	for _, app := range helmApps {
+		componentStatuses := map[string]*operatorv1alpha1.HelmComponentStatus{}
+		for _, cs := range app.Status.GetComponents() {
+			componentStatuses[cs.GetName()] = cs
+		}
+		releases += len(app.Status.GetComponents())
+		names := iopComponentNames(app)
+		for _, c := range app.Spec.GetComponents() {
+			name := names[c.GetName()]
+			if name == "" {
+				name = c.GetName()
+			}
+
+			cs := componentStatuses[c.GetName()]
+			vs := &istiov1alpha1.InstallStatus_VersionStatus{
+				Version: cs.GetChartVersion(),
+				Status:  releaseStatus(cs, c.GetVersion()),
+			}
+			if vs.Status == istiov1alpha1.InstallStatus_ERROR {
+				vs.Error = componentError(cs)
+				if !failed {
+					failed = true
+					status.Message = fmt.Sprintf("%s release %s failed: %s, see HelmApp %s/%s",
+						name, c.GetName(), vs.Error, app.GetNamespace(), app.GetName())
+				}
+			}
+
+			if status.ComponentStatus == nil {
+				status.ComponentStatus = map[string]*istiov1alpha1.InstallStatus_VersionStatus{}
+			}
+			status.ComponentStatus[name] = mergeVersionStatus(status.ComponentStatus[name], vs)
+		}
+
+		if msg := unsupportedMessage(app); msg != "" {
+			messages = append(messages, msg)
+		}
+		if msg := unmatchedOverlaysMessage(app, componentStatuses); msg != "" {
+			messages = append(messages, msg)
+		}
+		switch app.Status.GetPhase() {
+		case operatorv1alpha1.Phase_FAILED, operatorv1alpha1.Phase_DELETING:
+			// Errors of the HelmApp itself, e.g. its repository, are not reported by its components
+			statuses = append(statuses, overallStatus(app))
+		}
+	}
+	for _, msg := range messages {
+		status.Message = status.Message + "; " + msg
+	}
+
+	if releases == 0 {
+		status.Status = overallStatus(helmApp)
+		return status
+	}
+	for _, vs := range status.ComponentStatus {
+		statuses = append(statuses, vs.Status)
+	}
+	status.Status = aggregateStatus(statuses...)
+	return status
+}
+
+// unsupportedMessage lists the settings of the IstioOperator ignored since the charts of their releases have no
+// value for them, read from the annotation written when the HelmApp is generated
+func unsupportedMessage(helmApp *operatorv1alpha1.HelmApp) string {
+	v := helmApp.GetAnnotations()[constants.IOPUnsupportedAnnotation]
+	if v == "" {
+		return ""
+	}
+	unsupported := map[string][]string{}
+	if err := json.Unmarshal([]byte(v), &unsupported); err != nil || len(unsupported) == 0 {
+		return ""
+	}
+	releases := make([]string, 0, len(unsupported))
+	for release := range unsupported {
+		releases = append(releases, release)
+	}
+	sort.Strings(releases)
+	parts := make([]string, 0, len(releases))
+	for _, release := range releases {
+		parts = append(parts, fmt.Sprintf("%s: %s", release, strings.Join(unsupported[release], ", ")))
+	}
+	return "unsupported settings are ignored, " + strings.Join(parts, "; ")
+}
+
+// unmatchedOverlaysMessage lists the overlays and patches of the IstioOperator matching no resource rendered
+// by the chart of their release, as reported by the releases of the HelmApp
+func unmatchedOverlaysMessage(helmApp *operatorv1alpha1.HelmApp, componentStatuses map[string]*operatorv1alpha1.HelmComponentStatus) string {
+	var parts []string
+	for _, c := range helmApp.Spec.GetComponents() {
+		if unmatched := componentStatuses[c.GetName()].GetUnmatchedOverlays(); len(unmatched) > 0 {
+			parts = append(parts, fmt.Sprintf("%s: %s", c.GetName(), strings.Join(unmatched, ", ")))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "unmatched overlays are ignored, " + strings.Join(parts, "; ")
+}
+
+// componentError returns the reason and the message of a failed release
+func componentError(cs *operatorv1alpha1.HelmComponentStatus) string {
+	switch {
+	case cs.GetReason() == "":
+		return cs.GetMessage()
+	case cs.GetMessage() == "":
+		return cs.GetReason()
+	default:
+		return fmt.Sprintf("%s: %s", cs.GetReason(), cs.GetMessage())
+	}
+}
+
+// mergeVersionStatus merges the status of another release of the same Istio component
+func mergeVersionStatus(existing, vs *istiov1alpha1.InstallStatus_VersionStatus) *istiov1alpha1.InstallStatus_VersionStatus {
+	if existing == nil {
+		return vs
+	}
+	existing.Status = aggregateStatus(existing.Status, vs.Status)
+	if existing.Version == "" {
+		existing.Version = vs.Version
+	}
+	switch {
+	case existing.Error == "":
+		existing.Error = vs.Error
+	case vs.Error != "":
+		existing.Error = existing.Error + "; " + vs.Error
+	}
+	return existing
+}
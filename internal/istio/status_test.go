@@ -0,0 +1,260 @@
+package istio
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	operatorv1alpha1 "pluma.io/api/operator/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+)
+
+func TestInstallStatusFromHelmApp(t *testing.T) {
+	newHelmApp := func(version string, phase operatorv1alpha1.Phase, statuses ...*operatorv1alpha1.HelmComponentStatus) *operatorv1alpha1.HelmApp {
+		return &operatorv1alpha1.HelmApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "istio",
+				Namespace: "istio-system",
+				Annotations: map[string]string{
+					constants.IOPComponentsAnnotation: `{"istio-ingressgateway":"IngressGateways","internal-ingressgateway":"IngressGateways"}`,
+				},
+			},
+			Spec: &operatorv1alpha1.HelmAppSpec{
+				Components: []*operatorv1alpha1.HelmComponent{
+					{Name: "iop-istio-base", Chart: "base", Version: version},
+					{Name: "iop-istio-istiod", Chart: "istiod", Version: version},
+					{Name: "istio-ingressgateway", Chart: "gateway", Version: version},
+					{Name: "internal-ingressgateway", Chart: "gateway", Version: version},
+				},
+			},
+			Status: &operatorv1alpha1.HelmAppStatus{Phase: phase, Components: statuses},
+		}
+	}
+	deployed := func(name, chartVersion string) *operatorv1alpha1.HelmComponentStatus {
+		return &operatorv1alpha1.HelmComponentStatus{Name: name, Status: "deployed", Version: "1", ChartVersion: chartVersion}
+	}
+	// withoutBase is the HelmApp of a revision, whose base is shared
+	withoutBase := func(helmApp *operatorv1alpha1.HelmApp) *operatorv1alpha1.HelmApp {
+		helmApp.Spec.Components = helmApp.Spec.Components[1:]
+		return helmApp
+	}
+	newSharedBase := func(phase operatorv1alpha1.Phase, status *operatorv1alpha1.HelmComponentStatus) *operatorv1alpha1.HelmApp {
+		return &operatorv1alpha1.HelmApp{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        clusterHelmAppName,
+				Namespace:   "istio-system",
+				Annotations: map[string]string{constants.IOPComponentsAnnotation: `{"istio-base":"Base"}`},
+			},
+			Spec: &operatorv1alpha1.HelmAppSpec{
+				Components: []*operatorv1alpha1.HelmComponent{{Name: "istio-base", Chart: "base", Version: "1.24.2"}},
+			},
+			Status: &operatorv1alpha1.HelmAppStatus{Phase: phase, Components: []*operatorv1alpha1.HelmComponentStatus{status}},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		helmApp    *operatorv1alpha1.HelmApp
+		sharedBase *operatorv1alpha1.HelmApp
+		expected   *istiov1alpha1.InstallStatus
+	}{
+		{
+			name: "healthy",
+			helmApp: newHelmApp("1.22.8", operatorv1alpha1.Phase_SUCCEEDED,
+				deployed("iop-istio-base", "1.22.8"),
+				deployed("iop-istio-istiod", "1.22.8"),
+				deployed("istio-ingressgateway", "1.22.8"),
+				deployed("internal-ingressgateway", "1.22.8"),
+			),
+			expected: &istiov1alpha1.InstallStatus{
+				Status:  istiov1alpha1.InstallStatus_HEALTHY,
+				Message: "Components are installed by HelmApp istio-system/istio",
+				ComponentStatus: map[string]*istiov1alpha1.InstallStatus_VersionStatus{
+					"Base":            {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"Pilot":           {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"IngressGateways": {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_HEALTHY},
+				},
+			},
+		},
+		{
+			name: "version change",
+			helmApp: newHelmApp("1.23.0", operatorv1alpha1.Phase_RECONCILING,
+				deployed("iop-istio-base", "1.23.0"),
+				&operatorv1alpha1.HelmComponentStatus{Name: "iop-istio-istiod", Status: "pending-upgrade", ChartVersion: "1.22.8"},
+				deployed("istio-ingressgateway", "1.23.0"),
+				deployed("internal-ingressgateway", "1.22.8"),
+			),
+			expected: &istiov1alpha1.InstallStatus{
+				Status:  istiov1alpha1.InstallStatus_UPDATING,
+				Message: "Components are installed by HelmApp istio-system/istio",
+				ComponentStatus: map[string]*istiov1alpha1.InstallStatus_VersionStatus{
+					"Base":            {Version: "1.23.0", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"Pilot":           {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_UPDATING},
+					"IngressGateways": {Version: "1.23.0", Status: istiov1alpha1.InstallStatus_UPDATING},
+				},
+			},
+		},
+		{
+			name: "component being installed",
+			helmApp: newHelmApp("1.22.8", operatorv1alpha1.Phase_RECONCILING,
+				deployed("iop-istio-base", "1.22.8"),
+				deployed("iop-istio-istiod", "1.22.8"),
+				deployed("istio-ingressgateway", "1.22.8"),
+			),
+			expected: &istiov1alpha1.InstallStatus{
+				Status:  istiov1alpha1.InstallStatus_RECONCILING,
+				Message: "Components are installed by HelmApp istio-system/istio",
+				ComponentStatus: map[string]*istiov1alpha1.InstallStatus_VersionStatus{
+					"Base":            {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"Pilot":           {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"IngressGateways": {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_RECONCILING},
+				},
+			},
+		},
+		{
+			name: "failed gateway",
+			helmApp: newHelmApp("1.23.0", operatorv1alpha1.Phase_FAILED,
+				deployed("iop-istio-base", "1.23.0"),
+				&operatorv1alpha1.HelmComponentStatus{Name: "iop-istio-istiod", Status: "pending-upgrade", ChartVersion: "1.22.8"},
+				deployed("istio-ingressgateway", "1.23.0"),
+				&operatorv1alpha1.HelmComponentStatus{Name: "internal-ingressgateway", Status: "failed", ChartVersion: "1.23.0",
+					Reason: "SchemaValidationFailed", Message: "replicaCount must be integer"},
+			),
+			expected: &istiov1alpha1.InstallStatus{
+				Status: istiov1alpha1.InstallStatus_ERROR,
+				Message: "IngressGateways release internal-ingressgateway failed: SchemaValidationFailed: replicaCount must be integer, " +
+					"see HelmApp istio-system/istio",
+				ComponentStatus: map[string]*istiov1alpha1.InstallStatus_VersionStatus{
+					"Base":  {Version: "1.23.0", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"Pilot": {Version: "1.22.8", Status: istiov1alpha1.InstallStatus_UPDATING},
+					"IngressGateways": {Version: "1.23.0", Status: istiov1alpha1.InstallStatus_ERROR,
+						Error: "SchemaValidationFailed: replicaCount must be integer"},
+				},
+			},
+		},
+		{
+			name: "shared base",
+			helmApp: withoutBase(newHelmApp("1.24.2", operatorv1alpha1.Phase_SUCCEEDED,
+				deployed("iop-istio-istiod", "1.24.2"),
+				deployed("istio-ingressgateway", "1.24.2"),
+				deployed("internal-ingressgateway", "1.24.2"),
+			)),
+			sharedBase: newSharedBase(operatorv1alpha1.Phase_SUCCEEDED, deployed("istio-base", "1.24.2")),
+			expected: &istiov1alpha1.InstallStatus{
+				Status:  istiov1alpha1.InstallStatus_HEALTHY,
+				Message: "Components are installed by HelmApps istio-system/istio and istio-system/iop-cluster",
+				ComponentStatus: map[string]*istiov1alpha1.InstallStatus_VersionStatus{
+					"Base":            {Version: "1.24.2", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"Pilot":           {Version: "1.24.2", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"IngressGateways": {Version: "1.24.2", Status: istiov1alpha1.InstallStatus_HEALTHY},
+				},
+			},
+		},
+		{
+			name: "failed shared base",
+			helmApp: withoutBase(newHelmApp("1.24.2", operatorv1alpha1.Phase_SUCCEEDED,
+				deployed("iop-istio-istiod", "1.24.2"),
+				deployed("istio-ingressgateway", "1.24.2"),
+				deployed("internal-ingressgateway", "1.24.2"),
+			)),
+			sharedBase: newSharedBase(operatorv1alpha1.Phase_FAILED, &operatorv1alpha1.HelmComponentStatus{
+				Name: "istio-base", Status: "failed", ChartVersion: "1.24.2", Reason: "InstallFailed", Message: "conflict",
+				UnmatchedOverlays: []string{"ValidatingWebhookConfiguration/istiod-default-validator"},
+			}),
+			expected: &istiov1alpha1.InstallStatus{
+				Status: istiov1alpha1.InstallStatus_ERROR,
+				Message: "Base release istio-base failed: InstallFailed: conflict, see HelmApp istio-system/iop-cluster; " +
+					"unmatched overlays are ignored, istio-base: ValidatingWebhookConfiguration/istiod-default-validator",
+				ComponentStatus: map[string]*istiov1alpha1.InstallStatus_VersionStatus{
+					"Base": {Version: "1.24.2", Status: istiov1alpha1.InstallStatus_ERROR,
+						Error: "InstallFailed: conflict"},
+					"Pilot":           {Version: "1.24.2", Status: istiov1alpha1.InstallStatus_HEALTHY},
+					"IngressGateways": {Version: "1.24.2", Status: istiov1alpha1.InstallStatus_HEALTHY},
+				},
+			},
+		},
+		{
+			name:    "not reconciled yet",
+			helmApp: newHelmApp("1.22.8", operatorv1alpha1.Phase_UNKNOWN),
+			expected: &istiov1alpha1.InstallStatus{
+				Status:  istiov1alpha1.InstallStatus_NONE,
+				Message: "Components are installed by HelmApp istio-system/istio",
+				ComponentStatus: map[string]*istiov1alpha1.InstallStatus_VersionStatus{
+					"Base":            {Status: istiov1alpha1.InstallStatus_NONE},
+					"Pilot":           {Status: istiov1alpha1.InstallStatus_NONE},
+					"IngressGateways": {Status: istiov1alpha1.InstallStatus_NONE},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := installStatusFromHelmApp(tt.helmApp, tt.sharedBase)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("installStatusFromHelmApp() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregateStatus(t *testing.T) {
+	const (
+		none        = istiov1alpha1.InstallStatus_NONE
+		healthy     = istiov1alpha1.InstallStatus_HEALTHY
+		reconciling = istiov1alpha1.InstallStatus_RECONCILING
+		updating    = istiov1alpha1.InstallStatus_UPDATING
+		errored     = istiov1alpha1.InstallStatus_ERROR
+		action      = istiov1alpha1.InstallStatus_ACTION_REQUIRED
+	)
+	tests := []struct {
+		name     string
+		statuses []istiov1alpha1.InstallStatus_Status
+		expected istiov1alpha1.InstallStatus_Status
+	}{
+		{name: "no components", expected: none},
+		{name: "all none", statuses: []istiov1alpha1.InstallStatus_Status{none, none}, expected: none},
+		{name: "all healthy", statuses: []istiov1alpha1.InstallStatus_Status{healthy, healthy}, expected: healthy},
+		{name: "reconciling", statuses: []istiov1alpha1.InstallStatus_Status{healthy, reconciling}, expected: reconciling},
+		{name: "updating", statuses: []istiov1alpha1.InstallStatus_Status{healthy, updating}, expected: updating},
+		{name: "reconciling and updating", statuses: []istiov1alpha1.InstallStatus_Status{reconciling, updating, healthy}, expected: updating},
+		{name: "error", statuses: []istiov1alpha1.InstallStatus_Status{updating, errored, action}, expected: errored},
+		{name: "action required", statuses: []istiov1alpha1.InstallStatus_Status{updating, action}, expected: action},
+		{name: "none and healthy", statuses: []istiov1alpha1.InstallStatus_Status{none, healthy}, expected: reconciling},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := aggregateStatus(tt.statuses...); got != tt.expected {
+				t.Errorf("aggregateStatus() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_ComponentNames(t *testing.T) {
+	iop := &istiov1alpha1.IstioOperator{
+		TypeMeta:   metav1.TypeMeta{Kind: "IstioOperator", APIVersion: "install.istio.io/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system"},
+		Spec: &istiov1alpha1.IstioOperatorSpec{
+			Profile: "demo",
+		},
+	}
+
+	helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), iop)
+	if err != nil {
+		t.Fatalf("convertIopToHelmApp() error = %v", err)
+	}
+
+	expected := map[string]string{
+		"iop-istio-base":       "Base",
+		"iop-istio-istiod":     "Pilot",
+		"istio-ingressgateway": "IngressGateways",
+		"istio-egressgateway":  "EgressGateways",
+	}
+	if got := iopComponentNames(helmApp); !reflect.DeepEqual(got, expected) {
+		t.Errorf("iopComponentNames() = %v, want %v", got, expected)
+	}
+}
@@ -0,0 +1,117 @@
+package istio
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+	"google.golang.org/protobuf/types/known/structpb"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+)
+
+// DefaultIstioVersion is the version of the charts when neither the IstioOperator nor the configuration sets one
+const DefaultIstioVersion = "1.22.8"
+
+// tagVersionRegex matches the Istio version at the start of an image tag, e.g. `1.24.2` of `1.24.2-distroless`
+// or `1.25.0-beta.0` of `1.25.0-beta.0-debug`
+var tagVersionRegex = regexp.MustCompile(`^v?(\d+\.\d+\.\d+(?:-(?:alpha|beta|rc)\.\d+)?)(?:-[0-9A-Za-z.-]+)?$`)
+
+// partialVersionRegex matches the tags which look like a version without being a full one, e.g. `1.24` or
+// `1.2` of the YAML number `1.20`
+var partialVersionRegex = regexp.MustCompile(`^v?\d+(?:\.\d+)*(?:-[0-9A-Za-z.-]+)?$`)
+
+// compatibilityVersionConstraint are the chart versions with the compatibilityVersion value
+var compatibilityVersionConstraint, _ = semver.NewConstraint(">= 1.21.0-0")
+
+// invalidVersionError reports a version of the IstioOperator that no chart can be resolved from
+type invalidVersionError struct {
+	err error
+}
+
+func (e *invalidVersionError) Error() string {
+	return e.err.Error()
+}
+
+// istioVersion is the version of the charts and images of an IstioOperator
+type istioVersion struct {
+	// chart is the version of the Istio charts
+	chart string
+	// tag is the tag of the images, empty to use the one of the charts
+	tag string
+	// compatibilityVersion makes the charts keep the behavior of a previous version
+	compatibilityVersion string
+}
+
+// resolveVersion resolves the version of the charts of the IstioOperator, in order: the chart version
+// annotation, the version the image tag starts with, the default version. Tags which are not a version,
+// e.g. `latest` of a custom hub, only set the image tag. Tags like a partial version, e.g. `1.24`, are rejected
+// without the annotation rather than installing the charts of the default version.
+func resolveVersion(in *istiov1alpha1.IstioOperator, defaultVersion string) (*istioVersion, error) {
+	tag, err := imageTag(in.Spec.GetTag())
+	if err != nil {
+		return nil, &invalidVersionError{err: err}
+	}
+	v := &istioVersion{
+		tag:                  tag,
+		compatibilityVersion: in.Spec.GetCompatibilityVersion(),
+	}
+
+	if chartVersion := in.GetAnnotations()[constants.IOPChartVersionAnnotation]; chartVersion != "" {
+		v.chart = chartVersion
+	} else if m := tagVersionRegex.FindStringSubmatch(tag); m != nil {
+		v.chart = m[1]
+	} else if partialVersionRegex.MatchString(tag) {
+		return nil, &invalidVersionError{err: fmt.Errorf("tag %q is not a full Istio version, e.g. 1.24.2: "+
+			"quote the tag if it is a YAML number or set the annotation %s", tag, constants.IOPChartVersionAnnotation)}
+	} else if defaultVersion != "" {
+		v.chart = defaultVersion
+	} else {
+		v.chart = DefaultIstioVersion
+	}
+	if _, err := semver.NewVersion(v.chart); err != nil {
+		return nil, &invalidVersionError{err: fmt.Errorf("invalid chart version %q: %w", v.chart, err)}
+	}
+	return v, nil
+}
+
+// imageTag returns the tag of the IstioOperator, numbers are allowed as in `tag: 1.24`
+func imageTag(tag *structpb.Value) (string, error) {
+	switch k := tag.GetKind().(type) {
+	case nil, *structpb.Value_NullValue:
+		return "", nil
+	case *structpb.Value_StringValue:
+		return k.StringValue, nil
+	case *structpb.Value_NumberValue:
+		return strconv.FormatFloat(k.NumberValue, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("invalid tag %v: must be a string or a number", tag.AsInterface())
+	}
+}
+
+// supportsCompatibilityVersion reports whether the charts have the compatibilityVersion value
+func (v *istioVersion) supportsCompatibilityVersion() bool {
+	sv, err := semver.NewVersion(v.chart)
+	if err != nil {
+		return false
+	}
+	return compatibilityVersionConstraint.Check(sv)
+}
+
+// applyToValues sets the image tag and the compatibility version in the global values of the charts
+func (v *istioVersion) applyToValues(values map[string]interface{}) {
+	if v.tag != "" {
+		global, _ := values["global"].(map[string]interface{})
+		if global == nil {
+			global = map[string]interface{}{}
+			values["global"] = global
+		}
+		global["tag"] = v.tag
+	}
+	if v.compatibilityVersion != "" && v.supportsCompatibilityVersion() {
+		values["compatibilityVersion"] = v.compatibilityVersion
+	} else {
+		delete(values, "compatibilityVersion")
+	}
+}
@@ -0,0 +1,160 @@
+package istio
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	istiov1alpha1 "pluma.io/api/istio/v1alpha1"
+	"pluma.io/pluma-operator/internal/pkg/constants"
+)
+
+func TestResolveVersion(t *testing.T) {
+	tests := []struct {
+		name           string
+		iop            *istiov1alpha1.IstioOperator
+		defaultVersion string
+		expected       *istioVersion
+		wantErr        bool
+	}{
+		{
+			name:     "default version",
+			iop:      newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{}),
+			expected: &istioVersion{chart: DefaultIstioVersion},
+		},
+		{
+			name:           "configured default version",
+			iop:            newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{}),
+			defaultVersion: "1.24.2",
+			expected:       &istioVersion{chart: "1.24.2"},
+		},
+		{
+			name:     "version tag",
+			iop:      newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewStringValue("1.24.2")}),
+			expected: &istioVersion{chart: "1.24.2", tag: "1.24.2"},
+		},
+		{
+			name:     "distroless tag",
+			iop:      newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewStringValue("1.24.2-distroless")}),
+			expected: &istioVersion{chart: "1.24.2", tag: "1.24.2-distroless"},
+		},
+		{
+			name:     "pre-release tag",
+			iop:      newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewStringValue("1.25.0-beta.0-debug")}),
+			expected: &istioVersion{chart: "1.25.0-beta.0", tag: "1.25.0-beta.0-debug"},
+		},
+		{
+			name:           "tag which is not a version",
+			iop:            newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewStringValue("latest")}),
+			defaultVersion: "1.24.2",
+			expected:       &istioVersion{chart: "1.24.2", tag: "latest"},
+		},
+		{
+			name:    "numeric tag",
+			iop:     newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewNumberValue(1.24)}),
+			wantErr: true,
+		},
+		{
+			name:    "partial version tag",
+			iop:     newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewStringValue("1.24-distroless")}),
+			wantErr: true,
+		},
+		{
+			name: "numeric tag with chart version annotation",
+			iop: newTestIop("istio", map[string]string{constants.IOPChartVersionAnnotation: "1.24.2"},
+				&istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewNumberValue(1.24)}),
+			expected: &istioVersion{chart: "1.24.2", tag: "1.24"},
+		},
+		{
+			name: "chart version annotation",
+			iop: newTestIop("istio", map[string]string{constants.IOPChartVersionAnnotation: "1.24.1"},
+				&istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewStringValue("1.24.2-custom.1"), CompatibilityVersion: "1.23"}),
+			expected: &istioVersion{chart: "1.24.1", tag: "1.24.2-custom.1", compatibilityVersion: "1.23"},
+		},
+		{
+			name:    "invalid chart version annotation",
+			iop:     newTestIop("istio", map[string]string{constants.IOPChartVersionAnnotation: "stable"}, &istiov1alpha1.IstioOperatorSpec{}),
+			wantErr: true,
+		},
+		{
+			name:    "invalid tag",
+			iop:     newTestIop("istio", nil, &istiov1alpha1.IstioOperatorSpec{Tag: structpb.NewBoolValue(true)}),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveVersion(tt.iop, tt.defaultVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("resolveVersion() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIstioVersion_applyToValues(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  *istioVersion
+		values   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name:     "tag and compatibility version",
+			version:  &istioVersion{chart: "1.24.2", tag: "1.24.2-distroless", compatibilityVersion: "1.23"},
+			values:   map[string]interface{}{"global": map[string]interface{}{"hub": "docker.io/istio", "tag": float64(1.24)}},
+			expected: map[string]interface{}{"global": map[string]interface{}{"hub": "docker.io/istio", "tag": "1.24.2-distroless"}, "compatibilityVersion": "1.23"},
+		},
+		{
+			name:     "charts without compatibility version",
+			version:  &istioVersion{chart: "1.20.8", compatibilityVersion: "1.19"},
+			values:   map[string]interface{}{"compatibilityVersion": "1.19"},
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.version.applyToValues(tt.values)
+			if !reflect.DeepEqual(tt.values, tt.expected) {
+				t.Errorf("applyToValues() = %v, want %v", tt.values, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIstioOperatorReconciler_convertIopToHelmApp_Version(t *testing.T) {
+	iop := &istiov1alpha1.IstioOperator{
+		TypeMeta:   metav1.TypeMeta{Kind: "IstioOperator", APIVersion: "install.istio.io/v1alpha1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "istio", Namespace: "istio-system"},
+		Spec: &istiov1alpha1.IstioOperatorSpec{
+			Profile:              "minimal",
+			Tag:                  structpb.NewStringValue("1.24.2-distroless"),
+			CompatibilityVersion: "1.23",
+		},
+	}
+
+	helmApp, err := newFakeReconciler().convertIopToHelmApp(context.Background(), iop)
+	if err != nil {
+		t.Fatalf("convertIopToHelmApp() error = %v", err)
+	}
+	for _, c := range helmApp.Spec.Components {
+		if c.Version != "1.24.2" {
+			t.Errorf("convertIopToHelmApp() component %s version = %q, want 1.24.2", c.Name, c.Version)
+		}
+	}
+	globalValues := helmApp.Spec.GlobalValues.AsMap()
+	if tag, _, _ := unstructured.NestedString(globalValues, "global", "tag"); tag != "1.24.2-distroless" {
+		t.Errorf("convertIopToHelmApp() global.tag = %q, want 1.24.2-distroless", tag)
+	}
+	if v := globalValues["compatibilityVersion"]; v != "1.23" {
+		t.Errorf("convertIopToHelmApp() compatibilityVersion = %v, want 1.23", v)
+	}
+}
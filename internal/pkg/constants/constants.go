@@ -12,3 +12,37 @@ const (
 	SourceFromIOP          = "pluma.io/source-from-iop"
 	IOPSourceRepoLabel     = "pluma.io/source-repo"
 )
+
+const (
+	ComponentLabel         = "pluma.io/component"
+	ValuesHashAnnotation   = "pluma.io/values-hash"
+	ValuesLayersAnnotation = "pluma.io/values-layers"
+)
+
+// Labels of the Helm releases, the hashes are truncated to fit the 63 characters of a label value
+const (
+	ReleaseValuesHashLabel   = "pluma.io/release-values-hash"
+	ReleaseChartHashLabel    = "pluma.io/chart-hash"
+	ReleaseOverlaysHashLabel = "pluma.io/overlays-hash"
+	// ReleaseCommitLabel holds the Git commit of the chart source, a commit is 40 characters
+	ReleaseCommitLabel = "pluma.io/commit"
+)
+
+// IOPComponentsAnnotation of the HelmApp generated from an IstioOperator maps the name of each
+// release to its Istio component, e.g. `{"iop-istio-istiod": "Pilot"}`
+const IOPComponentsAnnotation = "pluma.io/iop-components"
+
+// IOPClusterComponentsLabel marks the HelmApp of the base chart shared by the IstioOperators with a
+// revision, which is removed with the last of them
+const IOPClusterComponentsLabel = "pluma.io/iop-cluster-components"
+
+// ForceDeleteAnnotation of an IstioOperator deletes its control plane even though proxies still use it
+const ForceDeleteAnnotation = "action.pluma.io/force-delete"
+
+// IOPChartVersionAnnotation of an IstioOperator sets the version of the Istio charts, when its tag is
+// not a version or differs from it, e.g. `1.24.2` with the tag `1.24.2-custom`
+const IOPChartVersionAnnotation = "pluma.io/chart-version"
+
+// IOPUnsupportedAnnotation of the HelmApp generated from an IstioOperator lists the settings of each
+// release its chart has no value for, e.g. `{"istio-ingressgateway": ["k8s.service.clusterIP"]}`
+const IOPUnsupportedAnnotation = "pluma.io/iop-unsupported"
@@ -0,0 +1,310 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-logr/logr"
+)
+
+// Mask replaces the sensitive values
+const Mask = "******"
+
+// minTextLength is the length under which a sensitive value is masked in the values only,
+// replacing such short strings in free text would mask unrelated words.
+const minTextLength = 4
+
+// sensitiveWords are the last words of the keys whose values are always masked, e.g.
+// password, dbPassword, clientSecret or AUTH_TOKEN.
+var sensitiveWords = map[string]bool{
+	"password":    true,
+	"passwd":      true,
+	"secret":      true,
+	"token":       true,
+	"credential":  true,
+	"credentials": true,
+}
+
+// keyWord is only sensitive as the last word of a compound key, e.g. apiKey, privateKey or tls.key.
+// A bare key is the field of selectors and tolerations.
+const keyWord = "key"
+
+var pathIndex = regexp.MustCompile(`\[(\d+|\*)\]`)
+
+// Redactor masks the sensitive values of a HelmApp in everything the operator prints.
+// Values are sensitive when their key matches a built-in rule or their path one of the user paths.
+type Redactor struct {
+	paths    [][]string
+	secrets  map[string]struct{}
+	replacer *strings.Replacer
+}
+
+// New returns a redactor masking the built-in sensitive keys and the given JSON paths,
+// e.g. `$.global.proxy.credentials`, `pilot.env.API_TOKEN` or `gateways[*].auth`.
+func New(paths []string) *Redactor {
+	r := &Redactor{secrets: map[string]struct{}{}}
+	for _, p := range paths {
+		if segments := parsePath(p); len(segments) > 0 {
+			r.paths = append(r.paths, segments)
+		}
+	}
+	return r
+}
+
+func parsePath(path string) []string {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = pathIndex.ReplaceAllString(path, ".$1")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// AddValues records the sensitive values of values so that they are masked in free text
+func (r *Redactor) AddValues(values map[string]any) {
+	if r == nil {
+		return
+	}
+	r.walk(values, nil, false, func(v any) any {
+		s := toString(v)
+		if len(s) >= minTextLength {
+			if _, ok := r.secrets[s]; !ok {
+				r.secrets[s] = struct{}{}
+				r.replacer = nil
+			}
+		}
+		return v
+	})
+}
+
+// Values returns a copy of values where the sensitive values are masked
+func (r *Redactor) Values(values map[string]any) map[string]any {
+	if r == nil {
+		return values
+	}
+	out := r.walk(values, nil, false, func(any) any { return Mask })
+	if out == nil {
+		return nil
+	}
+	return out.(map[string]any)
+}
+
+// walk copies v, replacing the sensitive leaves with the result of mask
+func (r *Redactor) walk(v any, path []string, sensitive bool, mask func(any) any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			p := append(path[:len(path):len(path)], k)
+			out[k] = r.walk(val, p, sensitive || isSensitiveKey(k) || r.matchPath(p), mask)
+		}
+		return out
+	case []any:
+		if v == nil {
+			return v
+		}
+		out := make([]any, len(v))
+		for i, val := range v {
+			p := append(path[:len(path):len(path)], strconv.Itoa(i))
+			out[i] = r.walk(val, p, sensitive || r.matchPath(p), mask)
+		}
+		return out
+	case nil:
+		return v
+	default:
+		if sensitive {
+			return mask(v)
+		}
+		return v
+	}
+}
+
+func (r *Redactor) matchPath(path []string) bool {
+	for _, p := range r.paths {
+		if len(p) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range p {
+			if p[i] != "*" && p[i] != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// isSensitiveKey reports whether the last word of key is a sensitive word
+func isSensitiveKey(key string) bool {
+	words := splitWords(key)
+	if len(words) == 0 {
+		return false
+	}
+	last := words[len(words)-1]
+	return sensitiveWords[last] || (last == keyWord && len(words) > 1)
+}
+
+// splitWords splits a key on separators and camel case boundaries, in lower case
+func splitWords(key string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(key)
+	for i, c := range runes {
+		switch {
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+			continue
+		case unicode.IsUpper(c) && len(word) > 0 &&
+			(unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))):
+			words = append(words, string(word))
+			word = nil
+		}
+		word = append(word, unicode.ToLower(c))
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	return words
+}
+
+func toString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// String masks the recorded sensitive values in s
+func (r *Redactor) String(s string) string {
+	if r == nil || len(r.secrets) == 0 {
+		return s
+	}
+	if r.replacer == nil {
+		secrets := make([]string, 0, len(r.secrets))
+		for secret := range r.secrets {
+			secrets = append(secrets, secret)
+		}
+		// Mask the longest values first when a value contains another one
+		sort.Slice(secrets, func(i, j int) bool {
+			if len(secrets[i]) != len(secrets[j]) {
+				return len(secrets[i]) > len(secrets[j])
+			}
+			return secrets[i] < secrets[j]
+		})
+		oldnew := make([]string, 0, 2*len(secrets))
+		for _, secret := range secrets {
+			oldnew = append(oldnew, secret, Mask)
+		}
+		r.replacer = strings.NewReplacer(oldnew...)
+	}
+	return r.replacer.Replace(s)
+}
+
+// Error masks the recorded sensitive values in the message of err, the original error is kept for errors.Is and errors.As
+func (r *Redactor) Error(err error) error {
+	if r == nil || err == nil {
+		return err
+	}
+	msg := r.String(err.Error())
+	if msg == err.Error() {
+		return err
+	}
+	return &redactedError{msg: msg, err: err}
+}
+
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+
+func (e *redactedError) Unwrap() error { return e.err }
+
+// Logf returns a printf style logger masking the recorded sensitive values before calling logf
+func (r *Redactor) Logf(logf func(format string, v ...interface{})) func(format string, v ...interface{}) {
+	return func(format string, v ...interface{}) {
+		logf("%s", r.String(fmt.Sprintf(format, v...)))
+	}
+}
+
+// Logger returns a logger masking the recorded sensitive values in the messages, errors and values logged with l
+func (r *Redactor) Logger(l logr.Logger) logr.Logger {
+	if r == nil || l.GetSink() == nil {
+		return l
+	}
+	return l.WithSink(&sink{sink: l.GetSink(), redactor: r})
+}
+
+type sink struct {
+	sink     logr.LogSink
+	redactor *Redactor
+}
+
+var _ logr.LogSink = &sink{}
+
+func (s *sink) Init(info logr.RuntimeInfo) {
+	// Account for the frame of the redacting sink
+	info.CallDepth++
+	s.sink.Init(info)
+}
+
+func (s *sink) Enabled(level int) bool {
+	return s.sink.Enabled(level)
+}
+
+func (s *sink) Info(level int, msg string, keysAndValues ...interface{}) {
+	s.sink.Info(level, s.redactor.String(msg), s.values(keysAndValues)...)
+}
+
+func (s *sink) Error(err error, msg string, keysAndValues ...interface{}) {
+	s.sink.Error(s.redactor.Error(err), s.redactor.String(msg), s.values(keysAndValues)...)
+}
+
+func (s *sink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	return &sink{sink: s.sink.WithValues(s.values(keysAndValues)...), redactor: s.redactor}
+}
+
+func (s *sink) WithName(name string) logr.LogSink {
+	return &sink{sink: s.sink.WithName(name), redactor: s.redactor}
+}
+
+// values masks the logged values, the keys are kept as is
+func (s *sink) values(keysAndValues []interface{}) []interface{} {
+	out := make([]interface{}, len(keysAndValues))
+	for i, v := range keysAndValues {
+		if i%2 == 0 {
+			out[i] = v
+			continue
+		}
+		switch v := v.(type) {
+		case string:
+			out[i] = s.redactor.String(v)
+		case error:
+			out[i] = s.redactor.Error(v)
+		case map[string]any:
+			out[i] = s.redactor.Values(v)
+		default:
+			out[i] = v
+		}
+	}
+	return out
+}
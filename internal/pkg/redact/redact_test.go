@@ -0,0 +1,200 @@
+package redact
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+)
+
+func TestIsSensitiveKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{key: "password", expected: true},
+		{key: "dbPassword", expected: true},
+		{key: "AUTH_TOKEN", expected: true},
+		{key: "clientSecret", expected: true},
+		{key: "apiKey", expected: true},
+		{key: "tls.key", expected: true},
+		{key: "private-key", expected: true},
+		{key: "credentials", expected: true},
+		{key: "key", expected: false},
+		{key: "secretName", expected: false},
+		{key: "tokenAudience", expected: false},
+		{key: "monkey", expected: false},
+		{key: "hub", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := isSensitiveKey(tt.key); got != tt.expected {
+				t.Errorf("isSensitiveKey(%q) = %v, want %v", tt.key, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRedactor_Values(t *testing.T) {
+	tests := []struct {
+		name     string
+		paths    []string
+		values   map[string]any
+		expected map[string]any
+	}{
+		{
+			name:     "nil values",
+			values:   nil,
+			expected: nil,
+		},
+		{
+			name: "built-in rules",
+			values: map[string]any{
+				"global": map[string]any{
+					"hub":      "docker.io/istio",
+					"password": "s3cr3t",
+					"tolerations": []any{
+						map[string]any{"key": "node-role", "operator": "Exists"},
+					},
+				},
+				"credentials": map[string]any{"user": "admin", "pass": "admin"},
+				"apiKeys":     []any{"a", "b"},
+			},
+			expected: map[string]any{
+				"global": map[string]any{
+					"hub":      "docker.io/istio",
+					"password": Mask,
+					"tolerations": []any{
+						map[string]any{"key": "node-role", "operator": "Exists"},
+					},
+				},
+				"credentials": map[string]any{"user": Mask, "pass": Mask},
+				"apiKeys":     []any{"a", "b"},
+			},
+		},
+		{
+			name:  "user paths",
+			paths: []string{"$.pilot.env.LICENSE", "gateways[*].auth", "meshConfig.extensionProviders[0]"},
+			values: map[string]any{
+				"pilot": map[string]any{
+					"env": map[string]any{"LICENSE": "abcd-efgh", "PILOT_TRACE_SAMPLING": float64(1)},
+				},
+				"gateways": []any{
+					map[string]any{"name": "ingress", "auth": map[string]any{"user": "ops"}},
+					map[string]any{"name": "egress"},
+				},
+				"meshConfig": map[string]any{
+					"extensionProviders": []any{"first", "second"},
+				},
+			},
+			expected: map[string]any{
+				"pilot": map[string]any{
+					"env": map[string]any{"LICENSE": Mask, "PILOT_TRACE_SAMPLING": float64(1)},
+				},
+				"gateways": []any{
+					map[string]any{"name": "ingress", "auth": map[string]any{"user": Mask}},
+					map[string]any{"name": "egress"},
+				},
+				"meshConfig": map[string]any{
+					"extensionProviders": []any{Mask, "second"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.paths).Values(tt.values)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Values() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRedactor_String(t *testing.T) {
+	r := New([]string{"pilot.env.LICENSE"})
+	r.AddValues(map[string]any{
+		"password": "s3cr3t-pass",
+		"token":    "abc",
+		"pilot":    map[string]any{"env": map[string]any{"LICENSE": "s3cr3t-pass-2"}},
+		"hub":      "docker.io/istio",
+	})
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "no sensitive value",
+			text:     "failed to pull docker.io/istio/pilot",
+			expected: "failed to pull docker.io/istio/pilot",
+		},
+		{
+			name:     "sensitive values",
+			text:     `invalid value "s3cr3t-pass" and "s3cr3t-pass-2"`,
+			expected: `invalid value "******" and "******"`,
+		},
+		{
+			name:     "short values are not masked in text",
+			text:     "abc",
+			expected: "abc",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.String(tt.text); got != tt.expected {
+				t.Errorf("String() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRedactor_Error(t *testing.T) {
+	r := New(nil)
+	r.AddValues(map[string]any{"token": "my-token"})
+
+	cause := errors.New("bad token my-token")
+	err := r.Error(cause)
+	if err.Error() != "bad token ******" {
+		t.Errorf("Error() = %q, want %q", err.Error(), "bad token ******")
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("Error() should wrap the original error")
+	}
+	if r.Error(nil) != nil {
+		t.Errorf("Error(nil) should be nil")
+	}
+}
+
+func TestRedactor_Logger(t *testing.T) {
+	r := New(nil)
+	r.AddValues(map[string]any{"token": "my-token"})
+
+	var lines []string
+	l := r.Logger(funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{}))
+
+	l.WithValues("header", "Bearer my-token").Info("using my-token", "values", map[string]any{"token": "my-token"})
+	l.Error(errors.New("rejected my-token"), "failed")
+
+	for _, line := range lines {
+		if strings.Contains(line, "my-token") {
+			t.Errorf("Logger() logged a sensitive value: %s", line)
+		}
+	}
+	if len(lines) != 2 {
+		t.Errorf("Logger() logged %d lines, want 2", len(lines))
+	}
+
+	if got := r.Logger(logr.Discard()); got.GetSink() != nil {
+		t.Errorf("Logger() should keep a discard logger")
+	}
+}
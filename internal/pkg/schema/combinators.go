@@ -0,0 +1,183 @@
+package schema
+
+import (
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// patternCache holds the compiled regular expressions of patternProperties
+var patternCache sync.Map
+
+// compilePattern returns the compiled pattern, nil if it is not a valid regular expression
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil
+	}
+	patternCache.Store(pattern, re)
+	return re
+}
+
+// additionalPropertiesSchema returns the schema of additionalProperties, false if it is absent or a bool
+func additionalPropertiesSchema(schema *JSONSchema) (*JSONSchema, bool) {
+	switch additional := schema.AdditionalProperties.(type) {
+	case *JSONSchema:
+		return additional, additional != nil
+	case map[string]interface{}:
+		return toSchema(additional), true
+	}
+	return nil, false
+}
+
+// matchPatternProperties returns the schema of the patternProperties matching key, nil if none.
+// A key matching several patterns must match all of their schemas.
+func matchPatternProperties(schema *JSONSchema, key string) *JSONSchema {
+	if len(schema.PatternProperties) == 0 {
+		return nil
+	}
+
+	patterns := make([]string, 0, len(schema.PatternProperties))
+	for pattern := range schema.PatternProperties {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	var matched []*JSONSchema
+	for _, pattern := range patterns {
+		if re := compilePattern(pattern); re != nil && re.MatchString(key) {
+			matched = append(matched, schema.PatternProperties[pattern])
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return nil
+	case 1:
+		return matched[0]
+	default:
+		return &JSONSchema{AllOf: matched}
+	}
+}
+
+// hasCombinators reports whether the schema uses allOf, anyOf, oneOf or if/then/else
+func hasCombinators(schema *JSONSchema) bool {
+	return len(schema.AllOf) > 0 || len(schema.AnyOf) > 0 || len(schema.OneOf) > 0 || schema.If != nil
+}
+
+// flatten merges the combinators of schema applying to value into a single schema: every allOf
+// schema, the anyOf and oneOf schemas the value matches (all of them if it matches none) and
+// the then or else schema picked by if. The schema itself is returned when it has no combinators,
+// otherwise a new schema so that the shared schemas are never modified.
+func flatten(schema *JSONSchema, value interface{}) *JSONSchema {
+	return flattenSeen(schema, value, map[*JSONSchema]bool{})
+}
+
+func flattenSeen(schema *JSONSchema, value interface{}, seen map[*JSONSchema]bool) *JSONSchema {
+	if schema == nil || !hasCombinators(schema) || seen[schema] {
+		return schema
+	}
+	seen[schema] = true
+	defer delete(seen, schema)
+
+	base := *schema
+	base.AllOf, base.AnyOf, base.OneOf = nil, nil, nil
+	base.If, base.Then, base.Else = nil, nil, nil
+
+	branches := []*JSONSchema{&base}
+	branches = append(branches, schema.AllOf...)
+	branches = append(branches, matchingSchemas(schema.AnyOf, value)...)
+	branches = append(branches, matchingSchemas(schema.OneOf, value)...)
+	if schema.If != nil {
+		if matches(value, schema.If) {
+			branches = append(branches, schema.Then)
+		} else {
+			branches = append(branches, schema.Else)
+		}
+	}
+
+	merged := &JSONSchema{}
+	for _, branch := range branches {
+		mergeSchema(merged, flattenSeen(branch, value, seen))
+	}
+	return merged
+}
+
+// matchingSchemas returns the schemas value matches, all of them if it matches none
+func matchingSchemas(schemas []*JSONSchema, value interface{}) []*JSONSchema {
+	var matched []*JSONSchema
+	for _, schema := range schemas {
+		if matches(value, schema) {
+			matched = append(matched, schema)
+		}
+	}
+	if len(matched) == 0 {
+		return schemas
+	}
+	return matched
+}
+
+func matches(value interface{}, schema *JSONSchema) bool {
+	v := &validator{patterns: map[string]*regexp.Regexp{}}
+	return v.valid(value, schema, "")
+}
+
+// mergeSchema merges the keywords used to filter and default values of src into dst. Properties
+// defined by both must match both schemas, additionalProperties is the most permissive one.
+func mergeSchema(dst, src *JSONSchema) {
+	if src == nil {
+		return
+	}
+
+	if dst.Type == nil {
+		dst.Type = src.Type
+	}
+	if dst.Default == nil {
+		dst.Default = src.Default
+	}
+	dst.Required = append(dst.Required, src.Required...)
+	dst.Properties = mergeSchemaMaps(dst.Properties, src.Properties)
+	dst.PatternProperties = mergeSchemaMaps(dst.PatternProperties, src.PatternProperties)
+	dst.Items = combineSchemas(dst.Items, src.Items)
+
+	switch {
+	case dst.AdditionalProperties == true:
+	case src.AdditionalProperties == true:
+		dst.AdditionalProperties = true
+	default:
+		dstSchema, dstOK := additionalPropertiesSchema(dst)
+		srcSchema, srcOK := additionalPropertiesSchema(src)
+		if dstOK || srcOK {
+			dst.AdditionalProperties = combineSchemas(dstSchema, srcSchema)
+		} else if dst.AdditionalProperties == nil {
+			dst.AdditionalProperties = src.AdditionalProperties
+		}
+	}
+}
+
+func mergeSchemaMaps(dst, src map[string]*JSONSchema) map[string]*JSONSchema {
+	if len(src) == 0 {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]*JSONSchema, len(src))
+	}
+	for key, schema := range src {
+		dst[key] = combineSchemas(dst[key], schema)
+	}
+	return dst
+}
+
+// combineSchemas returns a schema matching both a and b
+func combineSchemas(a, b *JSONSchema) *JSONSchema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil || a == b:
+		return a
+	default:
+		return &JSONSchema{AllOf: []*JSONSchema{a, b}}
+	}
+}
@@ -0,0 +1,114 @@
+package schema
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ApplyDefaults fills the properties missing from values with the default of their schema, recursively
+// in objects, array items, patternProperties and additionalProperties. The properties set by chartValues,
+// the values.yaml of the chart, are left to Helm so that a schema default never overrides the chart default.
+// It returns the paths of the injected defaults, sorted.
+func (f *Filter) ApplyDefaults(values, chartValues map[string]interface{}) (map[string]interface{}, []string) {
+	if f.schema == nil {
+		return values, nil
+	}
+
+	var injected []string
+	out, _ := applyDefaults(values, chartValues, f.schema, "", &injected).(map[string]interface{})
+	if out == nil {
+		out = map[string]interface{}{}
+	}
+	sort.Strings(injected)
+	return out, injected
+}
+
+// applyDefaults returns a copy of value where the properties missing from both value and chartValue are set
+// to their default
+func applyDefaults(value, chartValue interface{}, schema *JSONSchema, path string, injected *[]string) interface{} {
+	if schema == nil {
+		return value
+	}
+	schema = flatten(schema, value)
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		chartMap, _ := chartValue.(map[string]interface{})
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			out[k] = val
+		}
+
+		// Inject the missing properties first, the injected objects are completed below
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop := schema.Properties[name]
+			if _, ok := out[name]; ok || prop == nil {
+				continue
+			}
+			if chartVal, ok := chartMap[name]; ok {
+				// The chart default wins, its missing properties may still be defaulted
+				if _, isMap := chartVal.(map[string]interface{}); isMap {
+					if nested, _ := applyDefaults(map[string]interface{}{}, chartVal, prop, joinPath(path, name), injected).(map[string]interface{}); len(nested) > 0 {
+						out[name] = nested
+					}
+				}
+				continue
+			}
+			if prop.Default != nil {
+				out[name] = copyValue(prop.Default)
+				*injected = append(*injected, joinPath(path, name))
+			}
+		}
+
+		for k, val := range out {
+			if _, ok := v[k]; !ok {
+				if _, ok := chartMap[k]; ok {
+					// Completed against the chart default above
+					continue
+				}
+			}
+			if prop, ok := schema.Properties[k]; ok {
+				out[k] = applyDefaults(val, chartMap[k], prop, joinPath(path, k), injected)
+			} else if patternSchema := matchPatternProperties(schema, k); patternSchema != nil {
+				out[k] = applyDefaults(val, chartMap[k], patternSchema, joinPath(path, k), injected)
+			} else if additional, ok := additionalPropertiesSchema(schema); ok {
+				out[k] = applyDefaults(val, chartMap[k], additional, joinPath(path, k), injected)
+			}
+		}
+		return out
+	case []interface{}:
+		// The list replaces the one of the chart, its items have no chart default
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = applyDefaults(item, nil, schema.Items, fmt.Sprintf("%s[%d]", path, i), injected)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// copyValue deep copies a JSON value so that the defaults of the schema are never shared
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			out[k] = copyValue(val)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, val := range v {
+			out[i] = copyValue(val)
+		}
+		return out
+	default:
+		return value
+	}
+}
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFilter_ApplyDefaults(t *testing.T) {
+	schemaJSON := `{
+		"type": "object",
+		"properties": {
+			"replicaCount": {"type": "integer", "default": 1},
+			"service": {
+				"type": "object",
+				"default": {},
+				"properties": {
+					"type": {"type": "string", "default": "LoadBalancer"},
+					"ports": {
+						"type": "array",
+						"items": {
+							"type": "object",
+							"properties": {"protocol": {"type": "string", "default": "TCP"}}
+						}
+					}
+				}
+			},
+			"labels": {"type": "object"},
+			"gateways": {
+				"type": "object",
+				"additionalProperties": {
+					"type": "object",
+					"properties": {"enabled": {"type": "boolean", "default": true}}
+				}
+			}
+		}
+	}`
+	var chartSchema JSONSchema
+	if err := json.Unmarshal([]byte(schemaJSON), &chartSchema); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name             string
+		values           map[string]interface{}
+		chartValues      map[string]interface{}
+		expected         map[string]interface{}
+		expectedInjected []string
+	}{
+		{
+			name:   "empty values",
+			values: nil,
+			expected: map[string]interface{}{
+				"replicaCount": float64(1),
+				"service":      map[string]interface{}{"type": "LoadBalancer"},
+			},
+			expectedInjected: []string{"replicaCount", "service", "service.type"},
+		},
+		{
+			name: "arrays and additional properties",
+			values: map[string]interface{}{
+				"replicaCount": float64(3),
+				"service": map[string]interface{}{
+					"type":  "ClusterIP",
+					"ports": []interface{}{map[string]interface{}{"port": float64(80)}},
+				},
+				"gateways": map[string]interface{}{
+					"ingress": map[string]interface{}{},
+					"egress":  map[string]interface{}{"enabled": false},
+				},
+			},
+			expected: map[string]interface{}{
+				"replicaCount": float64(3),
+				"service": map[string]interface{}{
+					"type":  "ClusterIP",
+					"ports": []interface{}{map[string]interface{}{"port": float64(80), "protocol": "TCP"}},
+				},
+				"gateways": map[string]interface{}{
+					"ingress": map[string]interface{}{"enabled": true},
+					"egress":  map[string]interface{}{"enabled": false},
+				},
+			},
+			expectedInjected: []string{"gateways.ingress.enabled", "service.ports[0].protocol"},
+		},
+		{
+			name:   "values.yaml defaults win over the schema defaults",
+			values: map[string]interface{}{"gateways": map[string]interface{}{"ingress": map[string]interface{}{}}},
+			chartValues: map[string]interface{}{
+				"replicaCount": float64(2),
+				"service":      map[string]interface{}{"ports": []interface{}{}},
+				"gateways":     map[string]interface{}{"ingress": map[string]interface{}{"enabled": false}},
+			},
+			expected: map[string]interface{}{
+				"service":  map[string]interface{}{"type": "LoadBalancer"},
+				"gateways": map[string]interface{}{"ingress": map[string]interface{}{}},
+			},
+			expectedInjected: []string{"service.type"},
+		},
+		{
+			name: "null is not defaulted",
+			values: map[string]interface{}{
+				"replicaCount": nil,
+				"service":      map[string]interface{}{"type": "NodePort"},
+			},
+			expected: map[string]interface{}{
+				"replicaCount": nil,
+				"service":      map[string]interface{}{"type": "NodePort"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, injected := NewFilter(&chartSchema).ApplyDefaults(tt.values, tt.chartValues)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ApplyDefaults() = %v, want %v", got, tt.expected)
+			}
+			if !reflect.DeepEqual(injected, tt.expectedInjected) {
+				t.Errorf("ApplyDefaults() injected = %v, want %v", injected, tt.expectedInjected)
+			}
+		})
+	}
+
+	// Defaults of the schema are never shared with the values
+	got, _ := NewFilter(&chartSchema).ApplyDefaults(nil, nil)
+	got["service"].(map[string]interface{})["type"] = "changed"
+	if def := chartSchema.Properties["service"].Default.(map[string]interface{}); len(def) != 0 {
+		t.Errorf("ApplyDefaults() modified the schema default: %v", def)
+	}
+}
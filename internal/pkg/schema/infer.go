@@ -0,0 +1,134 @@
+package schema
+
+import (
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// DefaultAllowedPaths are the free-form values of most charts, their keys are never dropped by an inferred schema
+var DefaultAllowedPaths = []string{
+	"global",
+	"annotations",
+	"labels",
+	"podAnnotations",
+	"podLabels",
+	"nodeSelector",
+	"affinity",
+	"resources",
+	"env",
+	"securityContext",
+	"podSecurityContext",
+	"containerSecurityContext",
+}
+
+// InferSchemaFromChart derives a permissive schema from the default values of a chart without
+// values.schema.json, the values of its dependencies are inferred under their name.
+// See InferSchema for the allowed paths.
+func InferSchemaFromChart(cp *chart.Chart, allowedPaths []string) *JSONSchema {
+	values := map[string]interface{}{}
+	for k, v := range cp.Values {
+		values[k] = v
+	}
+	for _, dep := range cp.Dependencies() {
+		for _, name := range dependencyKeys(cp, dep) {
+			depValues := map[string]interface{}{}
+			for k, v := range dep.Values {
+				depValues[k] = v
+			}
+			// Values of the parent chart override the ones of the dependency
+			if parentValues, ok := values[name].(map[string]interface{}); ok {
+				for k, v := range parentValues {
+					depValues[k] = v
+				}
+			}
+			values[name] = depValues
+		}
+	}
+
+	return InferSchema(values, allowedPaths)
+}
+
+// dependencyKeys returns the keys of the values of a dependency, its aliases or its name
+func dependencyKeys(cp *chart.Chart, dep *chart.Chart) []string {
+	var keys []string
+	if cp.Metadata != nil {
+		for _, d := range cp.Metadata.Dependencies {
+			if d != nil && d.Name == dep.Name() && d.Alias != "" {
+				keys = append(keys, d.Alias)
+			}
+		}
+	}
+	if len(keys) == 0 {
+		keys = append(keys, dep.Name())
+	}
+	return keys
+}
+
+// InferSchema derives a permissive schema from default values: objects only allow the keys of the
+// defaults, objects with an empty or null default and the allowed paths allow any key, arrays allow
+// any item and scalars any value. Allowed paths are dotted paths where `*` matches any key, a path
+// without dot matches the key at any depth, e.g. `podAnnotations`. DefaultAllowedPaths are always allowed.
+func InferSchema(values map[string]interface{}, allowedPaths []string) *JSONSchema {
+	allowed := make([][]string, 0, len(DefaultAllowedPaths)+len(allowedPaths))
+	for _, path := range append(append([]string{}, DefaultAllowedPaths...), allowedPaths...) {
+		if path != "" {
+			allowed = append(allowed, strings.Split(path, "."))
+		}
+	}
+	return inferSchema(values, nil, allowed)
+}
+
+func inferSchema(value interface{}, path []string, allowed [][]string) *JSONSchema {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 || isAllowedPath(path, allowed) {
+			return &JSONSchema{Type: "object", AdditionalProperties: true}
+		}
+		properties := make(map[string]*JSONSchema, len(v))
+		for k, val := range v {
+			properties[k] = inferSchema(val, append(path[:len(path):len(path)], k), allowed)
+		}
+		return &JSONSchema{Type: "object", Properties: properties}
+	case []interface{}:
+		return &JSONSchema{Type: "array"}
+	case string:
+		return &JSONSchema{Type: "string"}
+	case bool:
+		return &JSONSchema{Type: "boolean"}
+	case float64, int, int64:
+		return &JSONSchema{Type: "number"}
+	default:
+		// Null defaults allow any value
+		return &JSONSchema{}
+	}
+}
+
+// isAllowedPath reports whether the path matches one of the allowed paths
+func isAllowedPath(path []string, allowed [][]string) bool {
+	if len(path) == 0 {
+		return false
+	}
+	for _, segments := range allowed {
+		if len(segments) == 1 {
+			if segments[0] == path[len(path)-1] {
+				return true
+			}
+			continue
+		}
+		if len(segments) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range segments {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestInferSchema(t *testing.T) {
+	defaults := map[string]interface{}{
+		"replicaCount": float64(1),
+		"image":        map[string]interface{}{"repository": "nginx", "tag": ""},
+		"podAnnotations": map[string]interface{}{
+			"prometheus.io/scrape": "true",
+		},
+		"service": map[string]interface{}{"type": "ClusterIP", "extra": map[string]interface{}{}},
+		"ports":   []interface{}{map[string]interface{}{"port": float64(80)}},
+		"meshConfig": map[string]interface{}{
+			"defaultConfig": map[string]interface{}{"proxyMetadata": map[string]interface{}{"A": "b"}},
+		},
+		"tolerations": nil,
+	}
+
+	tests := []struct {
+		name         string
+		allowedPaths []string
+		input        map[string]interface{}
+		expected     map[string]interface{}
+		pruned       []string
+	}{
+		{
+			name: "unknown keys are pruned",
+			input: map[string]interface{}{
+				"replicaCount": float64(2),
+				"autoscalling": map[string]interface{}{"enabled": true},
+				"image":        map[string]interface{}{"tag": "1.25", "digest": "sha256:abc"},
+				"ports":        []interface{}{map[string]interface{}{"port": float64(8080), "name": "http"}},
+				"tolerations":  []interface{}{map[string]interface{}{"key": "dedicated"}},
+			},
+			expected: map[string]interface{}{
+				"replicaCount": float64(2),
+				"image":        map[string]interface{}{"tag": "1.25"},
+				"ports":        []interface{}{map[string]interface{}{"port": float64(8080), "name": "http"}},
+				"tolerations":  []interface{}{map[string]interface{}{"key": "dedicated"}},
+			},
+			pruned: []string{"autoscalling", "image.digest"},
+		},
+		{
+			name: "empty defaults and default allowed paths are open",
+			input: map[string]interface{}{
+				"podAnnotations": map[string]interface{}{"foo": "bar"},
+				"service":        map[string]interface{}{"extra": map[string]interface{}{"any": "thing"}},
+			},
+			expected: map[string]interface{}{
+				"podAnnotations": map[string]interface{}{"foo": "bar"},
+				"service":        map[string]interface{}{"extra": map[string]interface{}{"any": "thing"}},
+			},
+		},
+		{
+			name:         "allowed paths",
+			allowedPaths: []string{"meshConfig.*.proxyMetadata"},
+			input: map[string]interface{}{
+				"meshConfig": map[string]interface{}{
+					"defaultConfig": map[string]interface{}{
+						"proxyMetadata":                   map[string]interface{}{"ISTIO_META_DNS_CAPTURE": "true"},
+						"holdApplicationUntilProxyStarts": true,
+					},
+				},
+			},
+			expected: map[string]interface{}{
+				"meshConfig": map[string]interface{}{
+					"defaultConfig": map[string]interface{}{
+						"proxyMetadata": map[string]interface{}{"ISTIO_META_DNS_CAPTURE": "true"},
+					},
+				},
+			},
+			pruned: []string{"meshConfig.defaultConfig.holdApplicationUntilProxyStarts"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, pruned := NewFilter(InferSchema(defaults, tt.allowedPaths)).FilterValues(tt.input)
+			if !mapsEqual(result, tt.expected) {
+				t.Errorf("FilterValues() = %v, want %v", result, tt.expected)
+			}
+			if !reflect.DeepEqual(pruned, tt.pruned) {
+				t.Errorf("FilterValues() pruned = %v, want %v", pruned, tt.pruned)
+			}
+		})
+	}
+}
+
+func TestInferSchemaFromChart(t *testing.T) {
+	dep := &chart.Chart{
+		Metadata: &chart.Metadata{Name: "redis"},
+		Values:   map[string]interface{}{"port": float64(6379)},
+	}
+	cp := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:         "app",
+			Dependencies: []*chart.Dependency{{Name: "redis", Alias: "cache"}},
+		},
+		Values: map[string]interface{}{
+			"name":  "app",
+			"cache": map[string]interface{}{"password": ""},
+		},
+	}
+	cp.AddDependency(dep)
+
+	input := map[string]interface{}{
+		"name":  "app",
+		"cache": map[string]interface{}{"port": float64(6380), "password": "secret", "unknown": true},
+		"redis": map[string]interface{}{"port": float64(6380)},
+	}
+	expected := map[string]interface{}{
+		"name":  "app",
+		"cache": map[string]interface{}{"port": float64(6380), "password": "secret"},
+	}
+
+	result, pruned := NewFilter(InferSchemaFromChart(cp, nil)).FilterValues(input)
+	if !mapsEqual(result, expected) {
+		t.Errorf("FilterValues() = %v, want %v", result, expected)
+	}
+	if expectedPruned := []string{"cache.unknown", "redis"}; !reflect.DeepEqual(pruned, expectedPruned) {
+		t.Errorf("FilterValues() pruned = %v, want %v", pruned, expectedPruned)
+	}
+}
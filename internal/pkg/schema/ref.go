@@ -0,0 +1,259 @@
+package schema
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// resolver replaces the $ref of a schema by their target. Targets are shared, so recursive
+// schemas become cyclic graphs, which is fine as values are finite.
+type resolver struct {
+	root *JSONSchema
+	// visited holds the schemas whose children are resolved or being resolved
+	visited map[*JSONSchema]bool
+}
+
+// ResolveSchemaReferences resolves $ref references in the schema. References are JSON Pointers
+// in the same document (`#/$defs/values`, `#/definitions/port`, `#`) or relative JSON Pointers
+// (`1/properties/name`). As in draft-07 the keywords next to a $ref are ignored. References to
+// other documents are left unresolved.
+func ResolveSchemaReferences(schema *JSONSchema) (*JSONSchema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	r := &resolver{root: schema, visited: map[*JSONSchema]bool{}}
+	return r.resolve(schema, nil)
+}
+
+// resolve returns schema with every $ref replaced, location is the JSON Pointer tokens of schema in the document
+func (r *resolver) resolve(schema *JSONSchema, location []string) (*JSONSchema, error) {
+	if schema == nil {
+		return nil, nil
+	}
+
+	schema, location, err := r.follow(schema, location)
+	if err != nil {
+		return nil, err
+	}
+	if r.visited[schema] {
+		return schema, nil
+	}
+	r.visited[schema] = true
+
+	resolveMap := func(keyword string, m map[string]*JSONSchema) error {
+		for key, sub := range m {
+			resolved, err := r.resolve(sub, appendTokens(location, keyword, key))
+			if err != nil {
+				return err
+			}
+			m[key] = resolved
+		}
+		return nil
+	}
+	resolveList := func(keyword string, list []*JSONSchema) error {
+		for i, sub := range list {
+			resolved, err := r.resolve(sub, appendTokens(location, keyword, strconv.Itoa(i)))
+			if err != nil {
+				return err
+			}
+			list[i] = resolved
+		}
+		return nil
+	}
+	resolveOne := func(keyword string, sub **JSONSchema) error {
+		resolved, err := r.resolve(*sub, appendTokens(location, keyword))
+		if err != nil {
+			return err
+		}
+		*sub = resolved
+		return nil
+	}
+
+	for keyword, m := range map[string]map[string]*JSONSchema{
+		"properties":        schema.Properties,
+		"patternProperties": schema.PatternProperties,
+		"$defs":             schema.Defs,
+		"definitions":       schema.Definitions,
+	} {
+		if err := resolveMap(keyword, m); err != nil {
+			return nil, err
+		}
+	}
+	for keyword, list := range map[string][]*JSONSchema{
+		"allOf": schema.AllOf,
+		"anyOf": schema.AnyOf,
+		"oneOf": schema.OneOf,
+	} {
+		if err := resolveList(keyword, list); err != nil {
+			return nil, err
+		}
+	}
+	for keyword, sub := range map[string]**JSONSchema{
+		"items": &schema.Items,
+		"if":    &schema.If,
+		"then":  &schema.Then,
+		"else":  &schema.Else,
+		"not":   &schema.Not,
+	} {
+		if err := resolveOne(keyword, sub); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep the resolved schema of additionalProperties instead of the decoded map
+	if additional, ok := additionalPropertiesSchema(schema); ok && additional != nil {
+		resolved, err := r.resolve(additional, appendTokens(location, "additionalProperties"))
+		if err != nil {
+			return nil, err
+		}
+		schema.AdditionalProperties = resolved
+	}
+
+	return schema, nil
+}
+
+// follow returns the target of the $ref of schema and its location, following chained references
+func (r *resolver) follow(schema *JSONSchema, location []string) (*JSONSchema, []string, error) {
+	seen := map[string]bool{}
+	for schema.Ref != "" {
+		target, ok, err := r.targetLocation(schema.Ref, location)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			// Reference to another document
+			return schema, location, nil
+		}
+		key := "#/" + strings.Join(target, "/")
+		if seen[key] {
+			return nil, nil, fmt.Errorf("circular $ref %s", schema.Ref)
+		}
+		seen[key] = true
+
+		next, err := r.lookup(target)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to resolve $ref %s: %w", schema.Ref, err)
+		}
+		schema, location = next, target
+	}
+	return schema, location, nil
+}
+
+// targetLocation returns the JSON Pointer tokens of the target of ref, false for references to other documents
+func (r *resolver) targetLocation(ref string, location []string) ([]string, bool, error) {
+	// Relative JSON Pointer, e.g. `1/properties/name`
+	if ref[0] >= '0' && ref[0] <= '9' {
+		levels, pointer, _ := strings.Cut(ref, "/")
+		n, err := strconv.Atoi(levels)
+		if err != nil || n > len(location) {
+			return nil, false, fmt.Errorf("invalid relative $ref %s", ref)
+		}
+		tokens, err := pointerTokens(pointer)
+		if err != nil {
+			return nil, false, err
+		}
+		return appendTokens(location[:len(location)-n], tokens...), true, nil
+	}
+
+	if !strings.HasPrefix(ref, "#") {
+		return nil, false, nil
+	}
+	fragment, err := url.PathUnescape(strings.TrimPrefix(ref, "#"))
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid $ref %s: %w", ref, err)
+	}
+	if fragment != "" && !strings.HasPrefix(fragment, "/") {
+		// Anchors are not supported
+		return nil, false, nil
+	}
+	tokens, err := pointerTokens(strings.TrimPrefix(fragment, "/"))
+	return tokens, err == nil, err
+}
+
+// pointerTokens splits a JSON Pointer without its leading slash into unescaped tokens
+func pointerTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	tokens := strings.Split(pointer, "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// lookup returns the schema at the JSON Pointer tokens from the root
+func (r *resolver) lookup(tokens []string) (*JSONSchema, error) {
+	node := r.root
+	for i := 0; i < len(tokens); i++ {
+		if node == nil {
+			return nil, fmt.Errorf("no schema at /%s", strings.Join(tokens[:i], "/"))
+		}
+		keyword := tokens[i]
+
+		var m map[string]*JSONSchema
+		var list []*JSONSchema
+		switch keyword {
+		case "properties":
+			m = node.Properties
+		case "patternProperties":
+			m = node.PatternProperties
+		case "$defs":
+			m = node.Defs
+		case "definitions":
+			m = node.Definitions
+		case "allOf":
+			list = node.AllOf
+		case "anyOf":
+			list = node.AnyOf
+		case "oneOf":
+			list = node.OneOf
+		case "items":
+			node = node.Items
+			continue
+		case "if":
+			node = node.If
+			continue
+		case "then":
+			node = node.Then
+			continue
+		case "else":
+			node = node.Else
+			continue
+		case "not":
+			node = node.Not
+			continue
+		case "additionalProperties":
+			node, _ = additionalPropertiesSchema(node)
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported keyword %s in pointer /%s", keyword, strings.Join(tokens, "/"))
+		}
+
+		i++
+		if i == len(tokens) {
+			return nil, fmt.Errorf("missing key after %s", keyword)
+		}
+		if m != nil || list == nil {
+			node = m[tokens[i]]
+			continue
+		}
+		index, err := strconv.Atoi(tokens[i])
+		if err != nil || index < 0 || index >= len(list) {
+			return nil, fmt.Errorf("invalid index %s of %s", tokens[i], keyword)
+		}
+		node = list[index]
+	}
+	if node == nil {
+		return nil, fmt.Errorf("no schema at /%s", strings.Join(tokens, "/"))
+	}
+	return node, nil
+}
+
+func appendTokens(location []string, tokens ...string) []string {
+	out := make([]string, 0, len(location)+len(tokens))
+	return append(append(out, location...), tokens...)
+}
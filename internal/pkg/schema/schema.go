@@ -3,11 +3,12 @@ package schema
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"helm.sh/helm/v3/pkg/chart"
 )
 
-// JSONSchema represents a simplified JSON Schema structure for field filtering
+// JSONSchema represents a simplified JSON Schema structure for field filtering and validation
 type JSONSchema struct {
 	Type                 interface{}            `json:"type,omitempty"` // Can be string or []string
 	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
@@ -15,11 +16,37 @@ type JSONSchema struct {
 	Items                *JSONSchema            `json:"items,omitempty"`
 	Ref                  string                 `json:"$ref,omitempty"`
 	Defs                 map[string]*JSONSchema `json:"$defs,omitempty"`
+	Definitions          map[string]*JSONSchema `json:"definitions,omitempty"`
+	PatternProperties    map[string]*JSONSchema `json:"patternProperties,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+
+	// Validation keywords
+	Enum             []interface{} `json:"enum,omitempty"`
+	Const            interface{}   `json:"const,omitempty"`
+	Required         []string      `json:"required,omitempty"`
+	Pattern          string        `json:"pattern,omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty"`
+	ExclusiveMinimum interface{}   `json:"exclusiveMinimum,omitempty"` // Number, or bool in draft-04
+	ExclusiveMaximum interface{}   `json:"exclusiveMaximum,omitempty"` // Number, or bool in draft-04
+	MinLength        *int          `json:"minLength,omitempty"`
+	MaxLength        *int          `json:"maxLength,omitempty"`
+	MinItems         *int          `json:"minItems,omitempty"`
+	MaxItems         *int          `json:"maxItems,omitempty"`
+	OneOf            []*JSONSchema `json:"oneOf,omitempty"`
+	AnyOf            []*JSONSchema `json:"anyOf,omitempty"`
+	AllOf            []*JSONSchema `json:"allOf,omitempty"`
+	Not              *JSONSchema   `json:"not,omitempty"`
+	If               *JSONSchema   `json:"if,omitempty"`
+	Then             *JSONSchema   `json:"then,omitempty"`
+	Else             *JSONSchema   `json:"else,omitempty"`
 }
 
 // Filter handles JSON schema field filtering
 type Filter struct {
 	schema *JSONSchema
+	// pruned holds the paths of the values dropped by the last FilterValues
+	pruned []string
 }
 
 // NewFilter creates a new schema filter
@@ -50,126 +77,52 @@ func LoadSchemaFromChart(chart *chart.Chart) (*JSONSchema, error) {
 	return nil, fmt.Errorf("values.schema.json not found in chart")
 }
 
-// ResolveSchemaReferences resolves $ref references in the schema
-func ResolveSchemaReferences(schema *JSONSchema) (*JSONSchema, error) {
-	if schema == nil {
-		return nil, nil
-	}
-
-	// If this schema has a $ref, resolve it
-	if schema.Ref != "" {
-		resolved, err := resolveRef(schema, schema)
-		if err != nil {
-			return nil, err
-		}
-		return resolved, nil
-	}
-
-	// Recursively resolve references in properties
-	if schema.Properties != nil {
-		for key, prop := range schema.Properties {
-			resolved, err := ResolveSchemaReferences(prop)
-			if err != nil {
-				return nil, err
-			}
-			schema.Properties[key] = resolved
-		}
-	}
-
-	// Resolve references in items
-	if schema.Items != nil {
-		resolved, err := ResolveSchemaReferences(schema.Items)
-		if err != nil {
-			return nil, err
-		}
-		schema.Items = resolved
-	}
-
-	// Resolve references in additionalProperties if it's a schema object
-	if schema.AdditionalProperties != nil {
-		if additionalSchema, ok := schema.AdditionalProperties.(map[string]interface{}); ok {
-			// Convert to JSONSchema and resolve
-			additionalJSONSchema := &JSONSchema{}
-			if data, err := json.Marshal(additionalSchema); err == nil {
-				json.Unmarshal(data, additionalJSONSchema)
-				resolved, err := ResolveSchemaReferences(additionalJSONSchema)
-				if err != nil {
-					return nil, err
-				}
-				// Convert back to interface{}
-				if resolvedData, err := json.Marshal(resolved); err == nil {
-					var resolvedInterface interface{}
-					json.Unmarshal(resolvedData, &resolvedInterface)
-					schema.AdditionalProperties = resolvedInterface
-				}
-			}
-		}
-	}
-
-	return schema, nil
-}
-
-// resolveRef resolves a $ref reference within a schema
-func resolveRef(schema *JSONSchema, rootSchema *JSONSchema) (*JSONSchema, error) {
-	if schema.Ref == "" {
-		return schema, nil
-	}
-
-	// Handle simple $ref like "#/$defs/values"
-	if schema.Ref == "#/$defs/values" && rootSchema.Defs != nil {
-		if def, exists := rootSchema.Defs["values"]; exists {
-			// Resolve any references in the definition
-			return ResolveSchemaReferences(def)
-		}
-	}
-
-	// Handle other $ref patterns if needed
-	// For now, return the original schema if we can't resolve
-	return schema, nil
-}
-
-// FilterValues filters values based on the JSON schema (only field filtering)
-func (f *Filter) FilterValues(values map[string]interface{}) map[string]interface{} {
+// FilterValues filters values based on the JSON schema (only field filtering). It returns the
+// JSON paths of the dropped values alongside, sorted, e.g. `podAnnotations.foo` or `service.ports[0].nodePort`.
+func (f *Filter) FilterValues(values map[string]interface{}) (map[string]interface{}, []string) {
 	if f.schema == nil {
-		return values
+		return values, nil
 	}
 
-	return f.filterObject(values, f.schema)
+	f.pruned = nil
+	filtered := f.filterObject(values, f.schema, "")
+	sort.Strings(f.pruned)
+	return filtered, f.pruned
 }
 
-// filterObject recursively filters an object based on schema
-func (f *Filter) filterObject(obj map[string]interface{}, schema *JSONSchema) map[string]interface{} {
-	if schema == nil || schema.Properties == nil {
+// filterObject recursively filters an object based on schema, the combinators of the schema
+// matching the object are merged first
+func (f *Filter) filterObject(obj map[string]interface{}, schema *JSONSchema, path string) map[string]interface{} {
+	schema = flatten(schema, obj)
+	if schema == nil || (schema.Properties == nil && schema.PatternProperties == nil) {
 		return obj
 	}
 
 	filtered := make(map[string]interface{})
 
 	for key, value := range obj {
+		keyPath := joinPath(path, key)
 		// Check if the property is defined in the schema
+		// An explicit null of an allowed property is kept, it unsets the chart default
 		if propSchema, exists := schema.Properties[key]; exists {
 			// Recursively filter the value based on its schema
-			filteredValue := f.filterValue(value, propSchema)
-			if filteredValue != nil {
-				filtered[key] = filteredValue
-			}
+			filtered[key] = f.filterValue(value, propSchema, keyPath)
+			continue
+		}
+
+		// Property matching patternProperties
+		if patternSchema := matchPatternProperties(schema, key); patternSchema != nil {
+			filtered[key] = f.filterValue(value, patternSchema, keyPath)
+			continue
+		}
+
+		// Property not in schema, check additionalProperties
+		if additionalSchema, ok := additionalPropertiesSchema(schema); ok {
+			filtered[key] = f.filterValue(value, additionalSchema, keyPath)
+		} else if allowAdditional, ok := schema.AdditionalProperties.(bool); ok && allowAdditional {
+			filtered[key] = value
 		} else {
-			// Property not in schema, check additionalProperties
-			if schema.AdditionalProperties != nil {
-				if allowAdditional, ok := schema.AdditionalProperties.(bool); ok && allowAdditional {
-					filtered[key] = value
-				} else if additionalSchema, ok := schema.AdditionalProperties.(map[string]interface{}); ok {
-					// Additional properties schema
-					additionalJSONSchema := &JSONSchema{}
-					if data, err := json.Marshal(additionalSchema); err == nil {
-						json.Unmarshal(data, additionalJSONSchema)
-						filteredValue := f.filterValue(value, additionalJSONSchema)
-						if filteredValue != nil {
-							filtered[key] = filteredValue
-						}
-					}
-				}
-			}
+			f.pruned = append(f.pruned, keyPath)
 		}
 	}
 
@@ -177,10 +130,11 @@ func (f *Filter) filterObject(obj map[string]interface{}, schema *JSONSchema) ma
 }
 
 // filterValue filters a value based on its schema
-func (f *Filter) filterValue(value interface{}, schema *JSONSchema) interface{} {
+func (f *Filter) filterValue(value interface{}, schema *JSONSchema, path string) interface{} {
 	if schema == nil {
 		return value
 	}
+	schema = flatten(schema, value)
 
 	// Handle type field which can be string or []string
 	schemaType := f.getSchemaType(schema)
@@ -188,11 +142,11 @@ func (f *Filter) filterValue(value interface{}, schema *JSONSchema) interface{}
 	switch schemaType {
 	case "object":
 		if obj, ok := value.(map[string]interface{}); ok {
-			return f.filterObject(obj, schema)
+			return f.filterObject(obj, schema, path)
 		}
 	case "array":
 		if arr, ok := value.([]interface{}); ok {
-			return f.filterArray(arr, schema)
+			return f.filterArray(arr, schema, path)
 		}
 	case "string", "number", "integer", "boolean":
 		// For primitive types, just return the value
@@ -230,14 +184,14 @@ func (f *Filter) getSchemaType(schema *JSONSchema) string {
 }
 
 // filterArray filters an array based on schema
-func (f *Filter) filterArray(arr []interface{}, schema *JSONSchema) []interface{} {
+func (f *Filter) filterArray(arr []interface{}, schema *JSONSchema, path string) []interface{} {
 	if schema.Items == nil {
 		return arr
 	}
 
 	filtered := make([]interface{}, 0, len(arr))
-	for _, item := range arr {
-		filteredItem := f.filterValue(item, schema.Items)
+	for i, item := range arr {
+		filteredItem := f.filterValue(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))
 		if filteredItem != nil {
 			filtered = append(filtered, filteredItem)
 		}
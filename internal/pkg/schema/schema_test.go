@@ -3,10 +3,13 @@ package schema
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"reflect"
 	"strings"
 	"testing"
 
 	"helm.sh/helm/v3/pkg/chart"
+	"pluma.io/pluma-operator/internal/pkg/tools"
 )
 
 func TestFilter_FilterValues(t *testing.T) {
@@ -172,7 +175,7 @@ func TestFilter_FilterValues(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			filter := NewFilter(tt.schema)
-			result := filter.FilterValues(tt.input)
+			result, _ := filter.FilterValues(tt.input)
 
 			if !mapsEqual(result, tt.expected) {
 				t.Errorf("FilterValues() = %v, want %v", result, tt.expected)
@@ -219,7 +222,7 @@ func TestFilter_filterObject(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			filter := &Filter{schema: tt.schema}
-			result := filter.filterObject(tt.input, tt.schema)
+			result := filter.filterObject(tt.input, tt.schema, "")
 
 			if !mapsEqual(result, tt.expected) {
 				t.Errorf("filterObject() = %v, want %v", result, tt.expected)
@@ -228,6 +231,48 @@ func TestFilter_filterObject(t *testing.T) {
 	}
 }
 
+func TestFilter_FilterValues_NullOverride(t *testing.T) {
+	schema := &JSONSchema{
+		Type: "object",
+		Properties: map[string]*JSONSchema{
+			"podAnnotations": {Type: "object"},
+			"service": {
+				Type: "object",
+				Properties: map[string]*JSONSchema{
+					"type":        {Type: "string"},
+					"annotations": {Type: "object"},
+				},
+			},
+			"env": {Type: "object", AdditionalProperties: &JSONSchema{Type: "string"}},
+		},
+		AdditionalProperties: false,
+	}
+	global := map[string]interface{}{
+		"service": map[string]interface{}{"type": "LoadBalancer", "annotations": map[string]interface{}{"a": "b"}},
+		"env":     map[string]interface{}{"ISTIO_META_DNS_CAPTURE": "true"},
+	}
+	// The nulls of the component unset the keys of the global values and of the chart defaults
+	component := map[string]interface{}{
+		"podAnnotations": nil,
+		"service":        map[string]interface{}{"annotations": nil},
+		"env":            map[string]interface{}{"ISTIO_META_DNS_CAPTURE": nil},
+		"unknown":        nil,
+	}
+
+	filtered, pruned := NewFilter(schema).FilterValues(tools.MergeMaps(global, component))
+	expected := map[string]interface{}{
+		"podAnnotations": nil,
+		"service":        map[string]interface{}{"type": "LoadBalancer", "annotations": nil},
+		"env":            map[string]interface{}{"ISTIO_META_DNS_CAPTURE": nil},
+	}
+	if !reflect.DeepEqual(filtered, expected) {
+		t.Errorf("FilterValues() = %v, want %v", filtered, expected)
+	}
+	if !reflect.DeepEqual(pruned, []string{"unknown"}) {
+		t.Errorf("FilterValues() pruned = %v, want [unknown]", pruned)
+	}
+}
+
 func TestFilter_filterValue(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -277,7 +322,7 @@ func TestFilter_filterValue(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			filter := &Filter{schema: tt.schema}
-			result := filter.filterValue(tt.input, tt.schema)
+			result := filter.filterValue(tt.input, tt.schema, "")
 
 			if !valuesEqual(result, tt.expected) {
 				t.Errorf("filterValue() = %v, want %v", result, tt.expected)
@@ -337,7 +382,7 @@ func TestFilter_filterArray(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			filter := &Filter{schema: tt.schema}
-			result := filter.filterArray(tt.input, tt.schema)
+			result := filter.filterArray(tt.input, tt.schema, "")
 
 			if !slicesEqual(result, tt.expected) {
 				t.Errorf("filterArray() = %v, want %v", result, tt.expected)
@@ -509,7 +554,7 @@ func TestFilterWithResolvedSchema(t *testing.T) {
 		},
 	}
 
-	result := filter.FilterValues(input)
+	result, _ := filter.FilterValues(input)
 	if !mapsEqual(result, expected) {
 		t.Errorf("FilterValues() with resolved schema = %v, want %v", result, expected)
 	}
@@ -561,7 +606,7 @@ func TestFilterWithTypeArray(t *testing.T) {
 		"minReadySeconds": 30,
 	}
 
-	result := filter.FilterValues(input)
+	result, _ := filter.FilterValues(input)
 	if !mapsEqual(result, expected) {
 		t.Errorf("FilterValues() with type arrays = %v, want %v", result, expected)
 	}
@@ -662,7 +707,7 @@ func TestFilterWithRealIstioSchema(t *testing.T) {
 		},
 	}
 
-	result := filter.FilterValues(input)
+	result, _ := filter.FilterValues(input)
 
 	// Verify that only schema-defined properties are kept
 	if len(result) != 3 {
@@ -688,6 +733,326 @@ func TestFilterWithRealIstioSchema(t *testing.T) {
 	}
 }
 
+func TestResolveSchemaReferences_Pointers(t *testing.T) {
+	tests := []struct {
+		name        string
+		schemaJSON  string
+		expectError bool
+		// check inspects the resolved schema
+		check func(t *testing.T, schema *JSONSchema)
+	}{
+		{
+			name: "definitions and escaped pointers",
+			schemaJSON: `{
+				"type": "object",
+				"properties": {
+					"port": {"$ref": "#/definitions/port"},
+					"tls": {"$ref": "#/definitions/tls~1config"},
+					"mode": {"$ref": "#/definitions/with%20space"}
+				},
+				"definitions": {
+					"port": {"type": "integer"},
+					"tls/config": {"type": "object", "properties": {"mode": {"type": "string"}}},
+					"with space": {"type": "string"}
+				}
+			}`,
+			check: func(t *testing.T, schema *JSONSchema) {
+				if typeToString(schema.Properties["port"].Type) != "integer" {
+					t.Errorf("port = %v, want the port definition", schema.Properties["port"])
+				}
+				if schema.Properties["tls"].Properties["mode"] == nil {
+					t.Errorf("tls = %v, want the tls/config definition", schema.Properties["tls"])
+				}
+				if typeToString(schema.Properties["mode"].Type) != "string" {
+					t.Errorf("mode = %v, want the definition with a space", schema.Properties["mode"])
+				}
+			},
+		},
+		{
+			name: "chained and nested references",
+			schemaJSON: `{
+				"$ref": "#/$defs/values",
+				"$defs": {
+					"values": {"type": "object", "properties": {"service": {"$ref": "#/$defs/alias"}}},
+					"alias": {"$ref": "#/$defs/service"},
+					"service": {
+						"type": "object",
+						"properties": {"ports": {"type": "array", "items": {"$ref": "#/$defs/service/properties/port"}}, "port": {"type": "integer"}}
+					}
+				}
+			}`,
+			check: func(t *testing.T, schema *JSONSchema) {
+				item := schema.Properties["service"].Properties["ports"].Items
+				if item == nil || typeToString(item.Type) != "integer" || item.Ref != "" {
+					t.Errorf("service.ports.items = %v, want the port schema", item)
+				}
+			},
+		},
+		{
+			name: "relative pointer",
+			schemaJSON: `{
+				"type": "object",
+				"properties": {
+					"gateway": {
+						"type": "object",
+						"properties": {
+							"name": {"type": "string"},
+							"serviceName": {"$ref": "1/name"}
+						}
+					}
+				}
+			}`,
+			check: func(t *testing.T, schema *JSONSchema) {
+				gateway := schema.Properties["gateway"]
+				if gateway.Properties["serviceName"] != gateway.Properties["name"] {
+					t.Errorf("gateway.serviceName = %v, want the name schema", gateway.Properties["serviceName"])
+				}
+			},
+		},
+		{
+			name: "recursive schema",
+			schemaJSON: `{
+				"type": "object",
+				"properties": {"children": {"type": "array", "items": {"$ref": "#"}}}
+			}`,
+			check: func(t *testing.T, schema *JSONSchema) {
+				if schema.Properties["children"].Items != schema {
+					t.Errorf("children.items = %v, want the root schema", schema.Properties["children"].Items)
+				}
+			},
+		},
+		{
+			name: "combinators and additionalProperties",
+			schemaJSON: `{
+				"$defs": {"label": {"type": "string"}},
+				"allOf": [{"$ref": "#/$defs/label"}],
+				"if": {"$ref": "#/$defs/label"},
+				"additionalProperties": {"$ref": "#/$defs/label"},
+				"patternProperties": {"^x-": {"$ref": "#/$defs/label"}}
+			}`,
+			check: func(t *testing.T, schema *JSONSchema) {
+				label := schema.Defs["label"]
+				additional, _ := additionalPropertiesSchema(schema)
+				if schema.AllOf[0] != label || schema.If != label || additional != label || schema.PatternProperties["^x-"] != label {
+					t.Errorf("schema = %+v, want every $ref resolved to the label definition", schema)
+				}
+			},
+		},
+		{
+			name:        "circular reference",
+			schemaJSON:  `{"$defs": {"a": {"$ref": "#/$defs/b"}, "b": {"$ref": "#/$defs/a"}}, "properties": {"a": {"$ref": "#/$defs/a"}}}`,
+			expectError: true,
+		},
+		{
+			name:        "missing target",
+			schemaJSON:  `{"properties": {"a": {"$ref": "#/definitions/missing"}}}`,
+			expectError: true,
+		},
+		{
+			name:       "external reference is kept",
+			schemaJSON: `{"properties": {"a": {"$ref": "https://example.com/schema.json"}}}`,
+			check: func(t *testing.T, schema *JSONSchema) {
+				if schema.Properties["a"].Ref != "https://example.com/schema.json" {
+					t.Errorf("a = %v, want the unresolved reference", schema.Properties["a"])
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema JSONSchema
+			if err := json.Unmarshal([]byte(tt.schemaJSON), &schema); err != nil {
+				t.Fatalf("failed to parse schema: %v", err)
+			}
+			result, err := ResolveSchemaReferences(&schema)
+			if (err != nil) != tt.expectError {
+				t.Fatalf("ResolveSchemaReferences() error = %v, expectError %v", err, tt.expectError)
+			}
+			if tt.check != nil {
+				tt.check(t, result)
+			}
+		})
+	}
+}
+
+func TestFilterWithCombinators(t *testing.T) {
+	schemaJSON := `{
+		"type": "object",
+		"definitions": {
+			"resources": {"type": "object", "properties": {"cpu": {"type": "string"}, "memory": {"type": "string"}}}
+		},
+		"allOf": [
+			{"properties": {"name": {"type": "string"}}},
+			{"properties": {"resources": {"$ref": "#/definitions/resources"}}}
+		],
+		"properties": {
+			"service": {
+				"type": "object",
+				"properties": {"type": {"type": "string"}},
+				"if": {"properties": {"type": {"const": "LoadBalancer"}}},
+				"then": {"properties": {"loadBalancerIP": {"type": "string"}}},
+				"else": {"properties": {"clusterIP": {"type": "string"}}}
+			},
+			"probe": {
+				"oneOf": [
+					{"type": "object", "required": ["httpGet"], "properties": {"httpGet": {"type": "object"}, "port": {"type": "integer"}}},
+					{"type": "object", "required": ["exec"], "properties": {"exec": {"type": "object"}}}
+				]
+			},
+			"labels": {
+				"type": "object",
+				"patternProperties": {"^app\\.": {"type": "string"}},
+				"additionalProperties": false
+			}
+		}
+	}`
+
+	tests := []struct {
+		name     string
+		input    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "allOf properties are merged",
+			input: map[string]interface{}{
+				"name":      "gateway",
+				"resources": map[string]interface{}{"cpu": "100m", "gpu": "1"},
+				"unknown":   true,
+			},
+			expected: map[string]interface{}{
+				"name":      "gateway",
+				"resources": map[string]interface{}{"cpu": "100m"},
+			},
+		},
+		{
+			name: "then branch",
+			input: map[string]interface{}{
+				"service": map[string]interface{}{"type": "LoadBalancer", "loadBalancerIP": "1.2.3.4", "clusterIP": "10.0.0.1"},
+			},
+			expected: map[string]interface{}{
+				"service": map[string]interface{}{"type": "LoadBalancer", "loadBalancerIP": "1.2.3.4"},
+			},
+		},
+		{
+			name: "else branch",
+			input: map[string]interface{}{
+				"service": map[string]interface{}{"type": "ClusterIP", "loadBalancerIP": "1.2.3.4", "clusterIP": "10.0.0.1"},
+			},
+			expected: map[string]interface{}{
+				"service": map[string]interface{}{"type": "ClusterIP", "clusterIP": "10.0.0.1"},
+			},
+		},
+		{
+			name: "oneOf matching branch",
+			input: map[string]interface{}{
+				"probe": map[string]interface{}{"httpGet": map[string]interface{}{}, "port": float64(8080), "exec": map[string]interface{}{}},
+			},
+			expected: map[string]interface{}{
+				"probe": map[string]interface{}{"httpGet": map[string]interface{}{}, "port": float64(8080), "exec": map[string]interface{}{}},
+			},
+		},
+		{
+			name: "oneOf without matching branch keeps the properties of every branch",
+			input: map[string]interface{}{
+				"probe": map[string]interface{}{"port": float64(8080), "tcpSocket": map[string]interface{}{}},
+			},
+			expected: map[string]interface{}{
+				"probe": map[string]interface{}{"port": float64(8080)},
+			},
+		},
+		{
+			name: "pattern properties",
+			input: map[string]interface{}{
+				"labels": map[string]interface{}{"app.kubernetes.io/name": "gateway", "team": "mesh"},
+			},
+			expected: map[string]interface{}{
+				"labels": map[string]interface{}{"app.kubernetes.io/name": "gateway"},
+			},
+		},
+	}
+
+	var chartSchema JSONSchema
+	if err := json.Unmarshal([]byte(schemaJSON), &chartSchema); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+	resolved, err := ResolveSchemaReferences(&chartSchema)
+	if err != nil {
+		t.Fatalf("ResolveSchemaReferences() error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := NewFilter(resolved).FilterValues(tt.input)
+			if !mapsEqual(result, tt.expected) {
+				t.Errorf("FilterValues() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFilterWithGatewaySchemaFixture(t *testing.T) {
+	data, err := os.ReadFile("testdata/gateway.values.schema.json")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	chartSchema, err := LoadSchemaFromChart(&chart.Chart{Raw: []*chart.File{{Name: "values.schema.json", Data: data}}})
+	if err != nil {
+		t.Fatalf("LoadSchemaFromChart() error = %v", err)
+	}
+
+	input := map[string]interface{}{
+		"name":         "istio-ingressgateway",
+		"replicaCount": float64(2),
+		"labels":       map[string]interface{}{"istio": "ingressgateway"},
+		"global":       map[string]interface{}{"hub": "docker.io/istio", "tag": "1.25.5"},
+		"autoscaling": map[string]interface{}{
+			"enabled":     true,
+			"minReplicas": float64(1),
+			"maxReplicas": float64(5),
+			"targetCPU":   float64(80),
+		},
+		"service": map[string]interface{}{
+			"type":  "LoadBalancer",
+			"ports": []interface{}{map[string]interface{}{"name": "http2", "port": float64(80), "nodePort": float64(30080)}},
+		},
+		"podDisruptionBudget": map[string]interface{}{"minAvailable": "50%"},
+		// Values of the istiod chart are not gateway values
+		"pilot":      map[string]interface{}{"enabled": true},
+		"meshConfig": map[string]interface{}{"accessLogFile": "/dev/stdout"},
+	}
+	expected := map[string]interface{}{
+		"name":         "istio-ingressgateway",
+		"replicaCount": float64(2),
+		"labels":       map[string]interface{}{"istio": "ingressgateway"},
+		"global":       map[string]interface{}{"hub": "docker.io/istio", "tag": "1.25.5"},
+		"autoscaling": map[string]interface{}{
+			"enabled":     true,
+			"minReplicas": float64(1),
+			"maxReplicas": float64(5),
+		},
+		"service": map[string]interface{}{
+			"type":  "LoadBalancer",
+			"ports": []interface{}{map[string]interface{}{"name": "http2", "port": float64(80)}},
+		},
+		"podDisruptionBudget": map[string]interface{}{"minAvailable": "50%"},
+	}
+
+	result, pruned := NewFilter(chartSchema).FilterValues(input)
+	if !mapsEqual(result, expected) {
+		t.Errorf("FilterValues() = %v, want %v", result, expected)
+	}
+	expectedPruned := []string{"autoscaling.targetCPU", "meshConfig", "pilot", "service.ports[0].nodePort"}
+	if strings.Join(pruned, ",") != strings.Join(expectedPruned, ",") {
+		t.Errorf("FilterValues() pruned = %v, want %v", pruned, expectedPruned)
+	}
+
+	// The filtered values are valid gateway values
+	if violations := Validate(chartSchema, result); len(violations) > 0 {
+		t.Errorf("Validate() = %v, want no violation", violations)
+	}
+}
+
 // Helper functions for deep comparison
 
 func mapsEqual(a, b map[string]interface{}) bool {
@@ -0,0 +1,316 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation is a value that does not match the schema
+type Violation struct {
+	// Path is the JSON path of the value, e.g. `pilot.replicaCount` or `gateways[0].name`
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s: %s", v.Path, v.Message)
+}
+
+// rootPath is the path of the values themselves
+const rootPath = "(root)"
+
+// Validate checks values against the schema and returns every violation, sorted by path.
+// Null values are skipped as Helm removes them before rendering.
+func Validate(schema *JSONSchema, values map[string]interface{}) []Violation {
+	if schema == nil {
+		return nil
+	}
+	v := &validator{patterns: map[string]*regexp.Regexp{}}
+	v.validate(values, schema, "")
+	sort.SliceStable(v.violations, func(i, j int) bool {
+		return v.violations[i].Path < v.violations[j].Path
+	})
+	return v.violations
+}
+
+type validator struct {
+	violations []Violation
+	patterns   map[string]*regexp.Regexp
+}
+
+func (v *validator) report(path, format string, args ...interface{}) {
+	if path == "" {
+		path = rootPath
+	}
+	v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// valid reports whether value matches schema without recording the violations
+func (v *validator) valid(value interface{}, schema *JSONSchema, path string) bool {
+	sub := &validator{patterns: v.patterns}
+	sub.validate(value, schema, path)
+	return len(sub.violations) == 0
+}
+
+func (v *validator) validate(value interface{}, schema *JSONSchema, path string) {
+	if schema == nil || value == nil {
+		return
+	}
+
+	if types := schemaTypes(schema); len(types) > 0 && !matchesAnyType(value, types) {
+		v.report(path, "expected %s, got %s", strings.Join(types, " or "), typeOf(value))
+		// Other keywords are meaningless for a value of the wrong type
+		return
+	}
+
+	if len(schema.Enum) > 0 && !containsValue(schema.Enum, value) {
+		v.report(path, "value %v is not one of %v", value, schema.Enum)
+	}
+	if schema.Const != nil && !equalValues(schema.Const, value) {
+		v.report(path, "value %v is not %v", value, schema.Const)
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(value, schema, path)
+	case []interface{}:
+		v.validateArray(value, schema, path)
+	case string:
+		v.validateString(value, schema, path)
+	case float64, int, int64:
+		v.validateNumber(toFloat(value), schema, path)
+	}
+
+	v.validateCombinators(value, schema, path)
+}
+
+func (v *validator) validateObject(obj map[string]interface{}, schema *JSONSchema, path string) {
+	for _, name := range schema.Required {
+		if _, ok := obj[name]; !ok {
+			v.report(path, "missing required property %s", name)
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		childPath := joinPath(path, k)
+		if prop, ok := schema.Properties[k]; ok {
+			v.validate(obj[k], prop, childPath)
+			continue
+		}
+		if patternSchema := matchPatternProperties(schema, k); patternSchema != nil {
+			v.validate(obj[k], patternSchema, childPath)
+			continue
+		}
+		if additional, ok := additionalPropertiesSchema(schema); ok {
+			v.validate(obj[k], additional, childPath)
+		} else if allowed, ok := schema.AdditionalProperties.(bool); ok && !allowed {
+			v.report(childPath, "property is not allowed")
+		}
+	}
+}
+
+func (v *validator) validateArray(arr []interface{}, schema *JSONSchema, path string) {
+	if schema.MinItems != nil && len(arr) < *schema.MinItems {
+		v.report(path, "expected at least %d items, got %d", *schema.MinItems, len(arr))
+	}
+	if schema.MaxItems != nil && len(arr) > *schema.MaxItems {
+		v.report(path, "expected at most %d items, got %d", *schema.MaxItems, len(arr))
+	}
+	for i, item := range arr {
+		v.validate(item, schema.Items, fmt.Sprintf("%s[%d]", path, i))
+	}
+}
+
+func (v *validator) validateString(s string, schema *JSONSchema, path string) {
+	length := utf8.RuneCountInString(s)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		v.report(path, "expected at least %d characters, got %d", *schema.MinLength, length)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		v.report(path, "expected at most %d characters, got %d", *schema.MaxLength, length)
+	}
+	if schema.Pattern != "" {
+		re, ok := v.patterns[schema.Pattern]
+		if !ok {
+			var err error
+			if re, err = regexp.Compile(schema.Pattern); err != nil {
+				v.report(path, "invalid pattern %q in schema: %v", schema.Pattern, err)
+				return
+			}
+			v.patterns[schema.Pattern] = re
+		}
+		if !re.MatchString(s) {
+			v.report(path, "value %q does not match pattern %q", s, schema.Pattern)
+		}
+	}
+}
+
+func (v *validator) validateNumber(n float64, schema *JSONSchema, path string) {
+	if schema.Minimum != nil {
+		if exclusive, _ := schema.ExclusiveMinimum.(bool); exclusive && n <= *schema.Minimum {
+			v.report(path, "value %v must be greater than %v", n, *schema.Minimum)
+		} else if n < *schema.Minimum {
+			v.report(path, "value %v must be at least %v", n, *schema.Minimum)
+		}
+	}
+	if schema.Maximum != nil {
+		if exclusive, _ := schema.ExclusiveMaximum.(bool); exclusive && n >= *schema.Maximum {
+			v.report(path, "value %v must be less than %v", n, *schema.Maximum)
+		} else if n > *schema.Maximum {
+			v.report(path, "value %v must be at most %v", n, *schema.Maximum)
+		}
+	}
+	if limit, ok := schema.ExclusiveMinimum.(float64); ok && n <= limit {
+		v.report(path, "value %v must be greater than %v", n, limit)
+	}
+	if limit, ok := schema.ExclusiveMaximum.(float64); ok && n >= limit {
+		v.report(path, "value %v must be less than %v", n, limit)
+	}
+}
+
+func (v *validator) validateCombinators(value interface{}, schema *JSONSchema, path string) {
+	for _, sub := range schema.AllOf {
+		v.validate(value, sub, path)
+	}
+	if len(schema.AnyOf) > 0 {
+		matched := false
+		for _, sub := range schema.AnyOf {
+			if v.valid(value, sub, path) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.report(path, "value does not match any schema of anyOf")
+		}
+	}
+	if len(schema.OneOf) > 0 {
+		matched := 0
+		for _, sub := range schema.OneOf {
+			if v.valid(value, sub, path) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.report(path, "value matches %d schemas of oneOf, expected exactly one", matched)
+		}
+	}
+	if schema.Not != nil && v.valid(value, schema.Not, path) {
+		v.report(path, "value must not match the schema of not")
+	}
+	if schema.If != nil {
+		if v.valid(value, schema.If, path) {
+			v.validate(value, schema.Then, path)
+		} else {
+			v.validate(value, schema.Else, path)
+		}
+	}
+}
+
+// schemaTypes returns the types allowed by the schema, type can be a string or a list
+func schemaTypes(schema *JSONSchema) []string {
+	switch t := schema.Type.(type) {
+	case string:
+		return []string{t}
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, typeVal := range t {
+			if typeStr, ok := typeVal.(string); ok {
+				types = append(types, typeStr)
+			}
+		}
+		return types
+	}
+	return nil
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	actual := typeOf(value)
+	for _, t := range types {
+		if t == actual || (t == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+// typeOf returns the JSON Schema type of a value, whole numbers are integers
+func typeOf(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case int, int64:
+		return "integer"
+	case float64:
+		if value == math.Trunc(value) && !math.IsInf(value, 0) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func toFloat(value interface{}) float64 {
+	switch value := value.(type) {
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case float64:
+		return value
+	}
+	return 0
+}
+
+func containsValue(list []interface{}, value interface{}) bool {
+	for _, item := range list {
+		if equalValues(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares JSON values, numbers are compared by value whatever their Go type
+func equalValues(a, b interface{}) bool {
+	if typeOf(a) == "integer" || typeOf(a) == "number" {
+		return (typeOf(b) == "integer" || typeOf(b) == "number") && toFloat(a) == toFloat(b)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// toSchema converts a schema decoded as a map, e.g. additionalProperties, to a JSONSchema
+func toSchema(m map[string]interface{}) *JSONSchema {
+	schema := &JSONSchema{}
+	if data, err := json.Marshal(m); err == nil {
+		_ = json.Unmarshal(data, schema)
+	}
+	return schema
+}
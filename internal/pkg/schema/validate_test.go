@@ -0,0 +1,120 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	schemaJSON := `{
+		"type": "object",
+		"required": ["image"],
+		"properties": {
+			"replicaCount": {"type": "integer", "minimum": 1, "maximum": 10},
+			"ratio": {"type": "number", "exclusiveMinimum": 0},
+			"image": {
+				"type": "object",
+				"properties": {
+					"pullPolicy": {"enum": ["Always", "IfNotPresent", "Never"]},
+					"tag": {"type": "string", "pattern": "^[0-9]+\\.[0-9]+\\.[0-9]+$"}
+				}
+			},
+			"hosts": {"type": "array", "minItems": 1, "items": {"type": "string", "minLength": 3}},
+			"labels": {"type": "object", "additionalProperties": {"type": "string"}},
+			"service": {"type": "object", "properties": {"port": {"type": "integer"}}, "additionalProperties": false},
+			"resources": {"type": ["object", "null"]},
+			"port": {"oneOf": [{"type": "integer"}, {"type": "string", "pattern": "^[0-9]+$"}]},
+			"annotations": {"type": "object", "patternProperties": {"^pluma\\.io/": {"type": "string"}}, "additionalProperties": false},
+			"tls": {
+				"type": "object",
+				"if": {"properties": {"mode": {"const": "SIMPLE"}}},
+				"then": {"required": ["credentialName"]},
+				"else": {"properties": {"credentialName": {"not": {}}}}
+			},
+			"profile": {"not": {"const": "remote"}}
+		}
+	}`
+	var chartSchema JSONSchema
+	if err := json.Unmarshal([]byte(schemaJSON), &chartSchema); err != nil {
+		t.Fatalf("failed to parse schema: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		values   map[string]interface{}
+		expected []Violation
+	}{
+		{
+			name: "valid values",
+			values: map[string]interface{}{
+				"replicaCount": float64(2),
+				"ratio":        0.5,
+				"image":        map[string]interface{}{"pullPolicy": "Always", "tag": "1.25.5"},
+				"hosts":        []interface{}{"foo.example.com"},
+				"labels":       map[string]interface{}{"app": "istiod"},
+				"resources":    nil,
+				"port":         "8080",
+			},
+		},
+		{
+			name: "every violation is reported",
+			values: map[string]interface{}{
+				"replicaCount": "two",
+				"ratio":        float64(0),
+				"hosts":        []interface{}{"a"},
+				"labels":       map[string]interface{}{"version": float64(1)},
+				"service":      map[string]interface{}{"port": 80.5, "type": "LoadBalancer"},
+				"port":         "http",
+			},
+			expected: []Violation{
+				{Path: "(root)", Message: "missing required property image"},
+				{Path: "hosts[0]", Message: "expected at least 3 characters, got 1"},
+				{Path: "labels.version", Message: "expected string, got integer"},
+				{Path: "port", Message: "value matches 0 schemas of oneOf, expected exactly one"},
+				{Path: "ratio", Message: "value 0 must be greater than 0"},
+				{Path: "replicaCount", Message: "expected integer, got string"},
+				{Path: "service.port", Message: "expected integer, got number"},
+				{Path: "service.type", Message: "property is not allowed"},
+			},
+		},
+		{
+			name: "enum, pattern and bounds",
+			values: map[string]interface{}{
+				"replicaCount": float64(11),
+				"image":        map[string]interface{}{"pullPolicy": "Sometimes", "tag": "latest"},
+				"hosts":        []interface{}{},
+			},
+			expected: []Violation{
+				{Path: "hosts", Message: "expected at least 1 items, got 0"},
+				{Path: "image.pullPolicy", Message: "value Sometimes is not one of [Always IfNotPresent Never]"},
+				{Path: "image.tag", Message: `value "latest" does not match pattern "^[0-9]+\\.[0-9]+\\.[0-9]+$"`},
+				{Path: "replicaCount", Message: "value 11 must be at most 10"},
+			},
+		},
+		{
+			name: "pattern properties, if and not",
+			values: map[string]interface{}{
+				"image":       map[string]interface{}{},
+				"annotations": map[string]interface{}{"pluma.io/rev": float64(1), "team": "mesh"},
+				"tls":         map[string]interface{}{"mode": "SIMPLE"},
+				"profile":     "remote",
+			},
+			expected: []Violation{
+				{Path: "annotations.pluma.io/rev", Message: "expected string, got integer"},
+				{Path: "annotations.team", Message: "property is not allowed"},
+				{Path: "profile", Message: "value must not match the schema of not"},
+				{Path: "tls", Message: "missing required property credentialName"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Validate(&chartSchema, tt.values)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Validate() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
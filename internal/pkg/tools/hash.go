@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"sort"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// HashValues returns the sha256 of the canonical JSON encoding of the values, see CanonicalValues
+func HashValues(values map[string]any) (string, error) {
+	data, err := canonicalJSON(values)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// CanonicalValues returns the values as decoded from JSON: numbers are float64, lists []any and
+// maps map[string]any, so values compare equal whether they come from structpb, YAML or a Helm release
+func CanonicalValues(values map[string]any) (map[string]any, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// canonicalJSON encodes the canonical values, map keys are sorted so the encoding is stable
+func canonicalJSON(values map[string]any) ([]byte, error) {
+	canonical, err := CanonicalValues(values)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(canonical)
+}
+
+// HashChart returns the sha256 of the content of a chart and its dependencies: metadata, templates,
+// files, default values and schema. Unlike the digest of the archive it is known for chart directories.
+func HashChart(ch *chart.Chart) (string, error) {
+	h := sha256.New()
+	if err := writeChart(h, ch); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func writeChart(h hash.Hash, ch *chart.Chart) error {
+	metadata, err := json.Marshal(ch.Metadata)
+	if err != nil {
+		return err
+	}
+	values, err := canonicalJSON(ch.Values)
+	if err != nil {
+		return err
+	}
+
+	write := func(name string, data []byte) {
+		// Each entry is its name and the hash of its data, so entries cannot run into each other
+		_, _ = h.Write([]byte(name))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(hex.EncodeToString(sha256Sum(data))))
+	}
+	write("Chart.yaml", metadata)
+	write("values", values)
+	write("values.schema.json", ch.Schema)
+	for _, files := range [][]*chart.File{ch.Templates, ch.Files} {
+		sorted := append([]*chart.File{}, files...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+		for _, f := range sorted {
+			write(f.Name, f.Data)
+		}
+	}
+
+	deps := append([]*chart.Chart{}, ch.Dependencies()...)
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name() < deps[j].Name() })
+	for _, dep := range deps {
+		write("charts/"+dep.Name(), nil)
+		if err := writeChart(h, dep); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sha256Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
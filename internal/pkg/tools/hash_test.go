@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestHashValues(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  map[string]any
+		equal bool
+	}{
+		{
+			name:  "ints and floats",
+			a:     map[string]any{"replicaCount": 2, "pilot": map[string]any{"cpu": int64(500)}},
+			b:     map[string]any{"replicaCount": float64(2), "pilot": map[string]any{"cpu": float64(500)}},
+			equal: true,
+		},
+		{
+			name:  "typed lists",
+			a:     map[string]any{"hosts": []string{"a", "b"}},
+			b:     map[string]any{"hosts": []any{"a", "b"}},
+			equal: true,
+		},
+		{
+			name:  "key order",
+			a:     map[string]any{"a": 1, "b": map[string]any{"c": true, "d": false}},
+			b:     map[string]any{"b": map[string]any{"d": false, "c": true}, "a": 1},
+			equal: true,
+		},
+		{
+			name: "different values",
+			a:    map[string]any{"replicaCount": 2},
+			b:    map[string]any{"replicaCount": 3},
+		},
+		{
+			name: "null is not absent",
+			a:    map[string]any{"resources": nil},
+			b:    map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := HashValues(tt.a)
+			if err != nil {
+				t.Fatalf("HashValues() error = %v", err)
+			}
+			b, err := HashValues(tt.b)
+			if err != nil {
+				t.Fatalf("HashValues() error = %v", err)
+			}
+			if (a == b) != tt.equal {
+				t.Errorf("HashValues() = %s and %s, want equal %v", a, b, tt.equal)
+			}
+		})
+	}
+}
+
+func TestHashChart(t *testing.T) {
+	newChart := func(template string) *chart.Chart {
+		ch := &chart.Chart{
+			Metadata:  &chart.Metadata{Name: "gateway", Version: "1.25.5"},
+			Values:    map[string]any{"replicaCount": 1},
+			Templates: []*chart.File{{Name: "templates/deployment.yaml", Data: []byte(template)}, {Name: "templates/service.yaml"}},
+		}
+		ch.AddDependency(&chart.Chart{Metadata: &chart.Metadata{Name: "base", Version: "1.25.5"}})
+		return ch
+	}
+
+	base, err := HashChart(newChart("kind: Deployment"))
+	if err != nil {
+		t.Fatalf("HashChart() error = %v", err)
+	}
+	same, _ := HashChart(newChart("kind: Deployment"))
+	if base != same {
+		t.Errorf("HashChart() = %s and %s for the same chart", base, same)
+	}
+
+	// Template files swapped in order hash the same
+	swapped := newChart("kind: Deployment")
+	swapped.Templates[0], swapped.Templates[1] = swapped.Templates[1], swapped.Templates[0]
+	if h, _ := HashChart(swapped); h != base {
+		t.Errorf("HashChart() = %s, want %s whatever the order of the templates", h, base)
+	}
+
+	changed, _ := HashChart(newChart("kind: DaemonSet"))
+	if changed == base {
+		t.Errorf("HashChart() did not change with the templates")
+	}
+
+	dep := newChart("kind: Deployment")
+	dep.Dependencies()[0].Values = map[string]any{"enabled": true}
+	if h, _ := HashChart(dep); h == base {
+		t.Errorf("HashChart() did not change with the values of a dependency")
+	}
+}
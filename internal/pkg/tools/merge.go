@@ -1,20 +1,199 @@
 package tools
 
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergeStrategy is how a list of the overlay is merged into the list of the base
+type MergeStrategy string
+
+const (
+	// MergeReplace replaces the list of the base, it is the default
+	MergeReplace MergeStrategy = "replace"
+	// MergeAppend appends the items of the overlay to the list of the base
+	MergeAppend MergeStrategy = "append"
+	// MergeByName merges the items with the same name and appends the others
+	MergeByName MergeStrategy = "mergeByName"
+	// mergeByKeyPrefix prefixes the strategy merging the items by another field, e.g. `mergeByKey:port`
+	mergeByKeyPrefix = "mergeByKey:"
+)
+
+// MergeStrategies are the list strategies by dotted path of map keys, e.g. `global.proxy.env`.
+// A `*` segment matches any key, list items don't add a segment.
+type MergeStrategies map[string]MergeStrategy
+
+// ParseMergeStrategies validates the strategies by path
+func ParseMergeStrategies(in map[string]string) (MergeStrategies, error) {
+	if len(in) == 0 {
+		return nil, nil
+	}
+	out := make(MergeStrategies, len(in))
+	for path, s := range in {
+		strategy := MergeStrategy(s)
+		if _, err := strategy.key(); err != nil {
+			return nil, fmt.Errorf("invalid merge strategy for %s: %w", path, err)
+		}
+		out[path] = strategy
+	}
+	return out, nil
+}
+
+// key returns the field identifying the items of a list, empty if items are not merged
+func (s MergeStrategy) key() (string, error) {
+	switch {
+	case s == "", s == MergeReplace, s == MergeAppend:
+		return "", nil
+	case s == MergeByName:
+		return "name", nil
+	case strings.HasPrefix(string(s), mergeByKeyPrefix) && len(s) > len(mergeByKeyPrefix):
+		return strings.TrimPrefix(string(s), mergeByKeyPrefix), nil
+	default:
+		return "", fmt.Errorf("unknown strategy %q, expected %s, %s, %s or %s<field>",
+			s, MergeReplace, MergeAppend, MergeByName, mergeByKeyPrefix)
+	}
+}
+
+// get returns the strategy of the path. When several patterns match, the most specific one wins so the
+// result does not depend on the map order: an exact path first, then the pattern with the fewest `*`, then
+// the one whose first `*` comes last, e.g. `global.*.env` over `*.proxy.env`.
+func (s MergeStrategies) get(path []string) MergeStrategy {
+	if strategy, ok := s[strings.Join(path, ".")]; ok {
+		return strategy
+	}
+	var best []string
+	strategy := MergeReplace
+	for p, st := range s {
+		segments := strings.Split(p, ".")
+		if !matchSegments(segments, path) {
+			continue
+		}
+		if best == nil || moreSpecific(segments, best) {
+			best, strategy = segments, st
+		}
+	}
+	return strategy
+}
+
+// matchSegments reports whether the pattern matches the path, a `*` segment matches any key
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// moreSpecific reports whether the pattern a is more specific than b, both matching the same path
+func moreSpecific(a, b []string) bool {
+	if wa, wb := countWildcards(a), countWildcards(b); wa != wb {
+		return wa < wb
+	}
+	for i := range a {
+		if (a[i] == "*") != (b[i] == "*") {
+			return b[i] == "*"
+		}
+	}
+	return false
+}
+
+func countWildcards(segments []string) int {
+	n := 0
+	for _, s := range segments {
+		if s == "*" {
+			n++
+		}
+	}
+	return n
+}
+
+// MergeMaps merges b into a, maps are merged recursively and lists are replaced.
+// An explicit null in b unsets the key: the null is kept so that Helm also drops the chart default.
 func MergeMaps(a, b map[string]any) map[string]any {
+	return MergeMapsWithStrategies(a, b, nil)
+}
+
+// MergeMapsWithStrategies merges b into a like MergeMaps, the lists are merged with the strategy of their path
+func MergeMapsWithStrategies(a, b map[string]any, strategies MergeStrategies) map[string]any {
+	return mergeMaps(a, b, nil, strategies)
+}
+
+func mergeMaps(a, b map[string]any, path []string, strategies MergeStrategies) map[string]any {
 	out := make(map[string]any, len(a))
 	for k, v := range a {
 		out[k] = v
 	}
 	for k, v := range b {
-		if v, ok := v.(map[string]any); ok {
-			if bv, ok := out[k]; ok {
-				if bv, ok := bv.(map[string]any); ok {
-					out[k] = MergeMaps(bv, v)
-					continue
-				}
+		p := append(path[:len(path):len(path)], k)
+		switch v := v.(type) {
+		case map[string]any:
+			if av, ok := out[k].(map[string]any); ok {
+				out[k] = mergeMaps(av, v, p, strategies)
+				continue
+			}
+		case []any:
+			if av, ok := out[k].([]any); ok {
+				out[k] = mergeLists(av, v, p, strategies)
+				continue
 			}
 		}
 		out[k] = v
 	}
 	return out
 }
+
+func mergeLists(a, b []any, path []string, strategies MergeStrategies) []any {
+	strategy := strategies.get(path)
+	switch strategy {
+	case MergeReplace:
+		return b
+	case MergeAppend:
+		out := make([]any, 0, len(a)+len(b))
+		return append(append(out, a...), b...)
+	}
+
+	key, err := strategy.key()
+	if err != nil || key == "" {
+		// Strategies are validated by ParseMergeStrategies
+		return b
+	}
+	out := make([]any, len(a), len(a)+len(b))
+	copy(out, a)
+	for _, item := range b {
+		if i := indexByKey(out, item, key); i >= 0 {
+			out[i] = deleteNulls(mergeMaps(out[i].(map[string]any), item.(map[string]any), path, strategies))
+			continue
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+// indexByKey returns the index of the item of list with the same key as item, -1 if there is none
+func indexByKey(list []any, item any, key string) int {
+	m, ok := item.(map[string]any)
+	if !ok || m[key] == nil {
+		return -1
+	}
+	for i, v := range list {
+		if v, ok := v.(map[string]any); ok && reflect.DeepEqual(v[key], m[key]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// deleteNulls removes the keys unset with null from a merged list item, Helm does not coalesce list items
+func deleteNulls(m map[string]any) map[string]any {
+	for k, v := range m {
+		if v == nil {
+			delete(m, k)
+		}
+	}
+	return m
+}
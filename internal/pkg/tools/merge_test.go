@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMergeMapsWithStrategies(t *testing.T) {
+	env := func(name, value string) map[string]any {
+		return map[string]any{"name": name, "value": value}
+	}
+
+	tests := []struct {
+		name       string
+		a          map[string]any
+		b          map[string]any
+		strategies MergeStrategies
+		expected   map[string]any
+	}{
+		{
+			name: "maps are merged and lists replaced by default",
+			a: map[string]any{
+				"global": map[string]any{"hub": "docker.io/istio", "tag": "1.22.8"},
+				"hosts":  []any{"a", "b"},
+			},
+			b: map[string]any{
+				"global": map[string]any{"tag": "1.23.0"},
+				"hosts":  []any{"c"},
+			},
+			expected: map[string]any{
+				"global": map[string]any{"hub": "docker.io/istio", "tag": "1.23.0"},
+				"hosts":  []any{"c"},
+			},
+		},
+		{
+			name: "null unsets a key",
+			a: map[string]any{
+				"global": map[string]any{"proxy": map[string]any{"image": "proxyv2"}},
+			},
+			b: map[string]any{
+				"global": map[string]any{"proxy": nil},
+			},
+			expected: map[string]any{
+				"global": map[string]any{"proxy": nil},
+			},
+		},
+		{
+			name:       "append",
+			a:          map[string]any{"hosts": []any{"a", "b"}},
+			b:          map[string]any{"hosts": []any{"c"}},
+			strategies: MergeStrategies{"hosts": MergeAppend},
+			expected:   map[string]any{"hosts": []any{"a", "b", "c"}},
+		},
+		{
+			name: "merge by name",
+			a: map[string]any{
+				"global": map[string]any{"proxy": map[string]any{"env": []any{
+					env("LOG_LEVEL", "info"), env("TRACING", "on"), env("REMOVED", "x"),
+				}}},
+			},
+			b: map[string]any{
+				"global": map[string]any{"proxy": map[string]any{"env": []any{
+					env("TRACING", "off"),
+					map[string]any{"name": "REMOVED", "value": nil},
+					env("NEW", "1"),
+				}}},
+			},
+			strategies: MergeStrategies{"global.proxy.env": MergeByName},
+			expected: map[string]any{
+				"global": map[string]any{"proxy": map[string]any{"env": []any{
+					env("LOG_LEVEL", "info"), env("TRACING", "off"), map[string]any{"name": "REMOVED"}, env("NEW", "1"),
+				}}},
+			},
+		},
+		{
+			name: "merge by key with a wildcard path",
+			a: map[string]any{
+				"gateways": map[string]any{"ingress": map[string]any{"tolerations": []any{
+					map[string]any{"key": "dedicated", "effect": "NoSchedule"},
+				}}},
+			},
+			b: map[string]any{
+				"gateways": map[string]any{"ingress": map[string]any{"tolerations": []any{
+					map[string]any{"key": "dedicated", "effect": "NoExecute"},
+					map[string]any{"key": "spot", "effect": "NoSchedule"},
+				}}},
+			},
+			strategies: MergeStrategies{"gateways.*.tolerations": "mergeByKey:key"},
+			expected: map[string]any{
+				"gateways": map[string]any{"ingress": map[string]any{"tolerations": []any{
+					map[string]any{"key": "dedicated", "effect": "NoExecute"},
+					map[string]any{"key": "spot", "effect": "NoSchedule"},
+				}}},
+			},
+		},
+		{
+			name:       "strategy of another path",
+			a:          map[string]any{"hosts": []any{"a"}},
+			b:          map[string]any{"hosts": []any{"b"}},
+			strategies: MergeStrategies{"global.hosts": MergeAppend},
+			expected:   map[string]any{"hosts": []any{"b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MergeMapsWithStrategies(tt.a, tt.b, tt.strategies)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("MergeMapsWithStrategies() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMergeStrategies(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        map[string]string
+		expectErr bool
+	}{
+		{name: "empty"},
+		{
+			name: "valid strategies",
+			in: map[string]string{
+				"a": "replace", "b": "append", "c": "mergeByName", "d": "mergeByKey:port",
+			},
+		},
+		{name: "unknown strategy", in: map[string]string{"a": "merge"}, expectErr: true},
+		{name: "missing key field", in: map[string]string{"a": "mergeByKey:"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMergeStrategies(tt.in)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ParseMergeStrategies() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestMergeStrategies_get(t *testing.T) {
+	strategies := MergeStrategies{
+		"*.proxy.env":      MergeAppend,
+		"global.*.env":     MergeByName,
+		"global.*.*":       MergeAppend,
+		"*.*.ports":        MergeAppend,
+		"gateways.*.ports": "mergeByKey:port",
+		"meshConfig.env":   MergeReplace,
+	}
+
+	tests := []struct {
+		path     string
+		expected MergeStrategy
+	}{
+		{path: "global.proxy.env", expected: MergeByName},
+		{path: "global.proxy.volumes", expected: MergeAppend},
+		{path: "gateways.ingress.ports", expected: "mergeByKey:port"},
+		{path: "values.ingress.ports", expected: MergeAppend},
+		{path: "meshConfig.env", expected: MergeReplace},
+		{path: "unknown", expected: MergeReplace},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			// the map order changes between runs, the strategy must not
+			for i := 0; i < 20; i++ {
+				if got := strategies.get(strings.Split(tt.path, ".")); got != tt.expected {
+					t.Fatalf("get() = %s, want %s", got, tt.expected)
+				}
+			}
+		})
+	}
+}
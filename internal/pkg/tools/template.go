@@ -0,0 +1,88 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+)
+
+// templateFuncs are the sprig functions available to values templates. Functions reading
+// the environment, the network or randomness are left out so that rendering is deterministic.
+var templateFuncs = []string{
+	"default", "empty", "coalesce", "ternary", "fail",
+	"lower", "upper", "title", "trim", "trimPrefix", "trimSuffix", "trunc",
+	"replace", "contains", "hasPrefix", "hasSuffix", "split", "splitList", "join",
+	"quote", "squote", "toString", "atoi", "int", "int64", "float64",
+	"semver", "semverCompare",
+	"list", "dict", "get", "hasKey", "keys", "has",
+	"b64enc", "b64dec", "sha256sum",
+}
+
+// TemplateFuncMap returns the restricted function map used to render values templates
+func TemplateFuncMap() template.FuncMap {
+	all := sprig.TxtFuncMap()
+	funcs := make(template.FuncMap, len(templateFuncs))
+	for _, name := range templateFuncs {
+		funcs[name] = all[name]
+	}
+	return funcs
+}
+
+// RenderValues renders the string values containing a template action with data, recursively.
+// Keys are never rendered and the rendered values stay strings.
+func RenderValues(values map[string]any, data any) (map[string]any, error) {
+	out, err := renderValue(values, "", data)
+	if err != nil {
+		return nil, err
+	}
+	return out.(map[string]any), nil
+}
+
+func renderValue(v any, path string, data any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			r, err := renderValue(val, joinPath(path, k), data)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			r, err := renderValue(val, fmt.Sprintf("%s[%d]", path, i), data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case string:
+		if !strings.Contains(v, "{{") {
+			return v, nil
+		}
+		tpl, err := template.New(path).Option("missingkey=error").Funcs(TemplateFuncMap()).Parse(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template at %s: %w", path, err)
+		}
+		var b strings.Builder
+		if err := tpl.Execute(&b, data); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", path, err)
+		}
+		return b.String(), nil
+	default:
+		return v, nil
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
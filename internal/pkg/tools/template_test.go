@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTemplateFuncMap(t *testing.T) {
+	funcs := TemplateFuncMap()
+	for _, name := range templateFuncs {
+		if funcs[name] == nil {
+			t.Errorf("TemplateFuncMap() has no function %s", name)
+		}
+	}
+	for _, name := range []string{"env", "expandenv", "getHostByName", "randAlphaNum", "now"} {
+		if _, ok := funcs[name]; ok {
+			t.Errorf("TemplateFuncMap() should not expose %s", name)
+		}
+	}
+}
+
+func TestRenderValues(t *testing.T) {
+	data := map[string]any{
+		"Namespace": "istio-system",
+		"ConfigMaps": map[string]map[string]string{
+			"cluster-info": {"name": "prod-east"},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		values    map[string]any
+		expected  map[string]any
+		expectErr bool
+	}{
+		{
+			name:     "nil values",
+			values:   nil,
+			expected: map[string]any{},
+		},
+		{
+			name: "no templates",
+			values: map[string]any{
+				"replicas": float64(2),
+				"image":    map[string]any{"tag": "1.22.8"},
+			},
+			expected: map[string]any{
+				"replicas": float64(2),
+				"image":    map[string]any{"tag": "1.22.8"},
+			},
+		},
+		{
+			name: "nested maps and lists",
+			values: map[string]any{
+				"global": map[string]any{
+					"istioNamespace": "{{ .Namespace }}",
+					"multiCluster": map[string]any{
+						"clusterName": `{{ index .ConfigMaps "cluster-info" "name" }}`,
+					},
+				},
+				"hosts": []any{"{{ .Namespace | upper }}", "static"},
+			},
+			expected: map[string]any{
+				"global": map[string]any{
+					"istioNamespace": "istio-system",
+					"multiCluster": map[string]any{
+						"clusterName": "prod-east",
+					},
+				},
+				"hosts": []any{"ISTIO-SYSTEM", "static"},
+			},
+		},
+		{
+			name:      "restricted function",
+			values:    map[string]any{"home": `{{ env "HOME" }}`},
+			expectErr: true,
+		},
+		{
+			name:      "missing key",
+			values:    map[string]any{"name": "{{ .Cluster }}"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid template",
+			values:    map[string]any{"name": "{{ .Namespace "},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderValues(tt.values, data)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("RenderValues() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RenderValues() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("RenderValues() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package profiles embeds the IstioOperator profiles, used when the profiles directory of the operator lacks one
+package profiles
+
+import "embed"
+
+// FS holds the profiles as `<name>.yaml`
+//
+//go:embed *.yaml
+var FS embed.FS